@@ -2,54 +2,29 @@ package main
 
 import (
 	"bytes"
-	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
-	"text/template"
 
 	"github.com/clintjedwards/polyfmt/v2"
-	"github.com/mitchellh/go-homedir"
-	"github.com/openai/openai-go"
-	"github.com/openai/openai-go/option"
 )
 
 const (
-	editorEnvVar         string = "EDITOR"
-	visualEnvVar         string = "VISUAL"
-	defaultEditor        string = "vi"
-	filePathFmt          string = "/tmp/%s_%s_%s.%s" // ex. /tmp/changelog_test_1.0.2
-	chatGPTTokenEnv      string = "CHATGPT_TOKEN"
-	chatGPTTokenFileName string = ".chatgpt_token"
+	editorEnvVar  string = "EDITOR"
+	visualEnvVar  string = "VISUAL"
+	defaultEditor string = "vi"
+	filePathFmt   string = "/tmp/%s_%s_%s.%s" // ex. /tmp/changelog_test_1.0.2
 )
 
-// changelogTemplate is the placeholder text for the input file
-const changelogTemplate = `// New release for {{.OrgAndRepo}} v{{.Version}}
+// changelogCommentHeaderFmt precedes the structured, grouped changelog draft in the editable
+// file. Lines starting with '//' are excluded from the final changelog.
+const changelogCommentHeaderFmt = `// New release for %s v%s
 //
 // All lines starting with '//' will be excluded from final changelog
 //
-// Commits since latest tag:
-{{- range .LastCommits}}
-// - {{ . }}
-{{- end}}
-//
-// Edit changelog below this comment. An example format has been given:
-
-## v{{.Version}} ({{.Date}})
-
-FEATURES:
-
-* **Feature Name**: Description about new feature this release [<short_commit_hash>]
-
-IMPROVEMENTS:
-
-* **Improvement Name**: Description about new improvement this release [<short_commit_hash>]
-
-BUG FIXES:
-
-* topic: Description of the bug. Example below [<short_commit_hash>]
-* api: Fix Go API using lease revocation via URL instead of body [<short_commit_hash>]
+// Edit changelog below this comment as needed.
+%s
 `
 
 // getEditorPath attempts to find a suitible editor
@@ -110,46 +85,14 @@ func getContentsFromUser(filePath string) ([]byte, error) {
 	return changelog, nil
 }
 
-func getChatGPTToken(tokenFile string) (token string, err error) {
-	token = os.Getenv(chatGPTTokenEnv)
-
-	if token != "" {
-		return token, nil
-	}
-
-	if tokenFile == "" {
-		home, err := homedir.Dir()
-		if err != nil {
-			return "", fmt.Errorf("could not get user home dir: %w", err)
-		}
-
-		tokenFile = fmt.Sprintf("%s/%s", home, chatGPTTokenFileName)
-	}
-
-	rawToken, err := setChatGPTTokenFromFile(tokenFile)
-	if err != nil {
-		return "", err
-	}
-
-	return string(rawToken), nil
-}
-
-func setChatGPTTokenFromFile(filename string) ([]byte, error) {
-	contents, err := os.ReadFile(filename)
-	if err != nil {
-		return nil, fmt.Errorf("could not find chatGPT token: %s; %w", filename, err)
-	}
-	if len(contents) == 0 {
-		return nil, fmt.Errorf("could not load chatGPT token contents empty: %s", filename)
-	}
-
-	token := bytes.TrimSpace(contents)
-	return token, nil
-}
-
-// handleChangelog opens a pre-populated file for editing and returns the final user contents
-func handleChangelog(orgAndRepo, version, date string, shortCommits []string, longCommits []string,
-	fmtter polyfmt.Formatter, useLLM bool,
+// handleChangelog opens a pre-populated file for editing and returns the final user contents.
+// The pre-populated draft groups parsedCommits into sections by conventional-commit kind and
+// scope, with malformedCommits folded into a collapsible details block so contributors can see
+// what was skipped. If generator is non-nil, the draft is first rewritten into a full changelog
+// via the configured LLM backend before being handed to the user for editing. host and hostname
+// determine the commit link format in the rendered draft.
+func handleChangelog(host gitHost, hostname, orgAndRepo, version, date string, parsedCommits []commitInfo, malformedCommits []string, longCommits []string,
+	fmtter polyfmt.Formatter, generator ChangelogGenerator, templatePath string,
 ) ([]byte, error) {
 	fmtter.Print("Creating changelog")
 
@@ -170,33 +113,18 @@ func handleChangelog(orgAndRepo, version, date string, shortCommits []string, lo
 		return nil, err
 	}
 
-	var changelogBuffer bytes.Buffer
-
-	tmpl := template.Must(template.New("").Parse(changelogTemplate))
-	err = tmpl.Execute(&changelogBuffer, struct {
-		OrgAndRepo  string
-		Version     string
-		Date        string
-		LastCommits []string
-	}{
-		OrgAndRepo:  orgAndRepo,
-		Version:     version,
-		Date:        date,
-		LastCommits: shortCommits,
-	})
+	draft, err := renderChangelogSection(host, hostname, orgAndRepo, version, date, parsedCommits, malformedCommits, templatePath)
 	if err != nil {
 		return nil, err
 	}
 
-	llmtoken, err := getChatGPTToken("")
-	if err != nil {
-		return nil, err
-	}
+	var changelogBuffer bytes.Buffer
+	fmt.Fprintf(&changelogBuffer, changelogCommentHeaderFmt, orgAndRepo, version, draft)
 
 	output := changelogBuffer.String()
 
-	if useLLM {
-		content, err := generateChangelogWithAI(llmtoken, changelogBuffer.String(), longCommits)
+	if generator != nil {
+		content, err := generator.Generate(changelogBuffer.String(), longCommits)
 		if err != nil {
 			return nil, err
 		}
@@ -218,51 +146,6 @@ func handleChangelog(orgAndRepo, version, date string, shortCommits []string, lo
 	return getContentsFromUser(filePath)
 }
 
-func generateChangelogWithAI(token, template string, commitMessages []string) (string, error) {
-	client := openai.NewClient(option.WithAPIKey(token))
-
-	prompt := "I want you to help me write a changelog. Below I will define the template I want you to follow" +
-		" and I'll pass you the commit messages you should use to change and fill in the template and give me a useable " +
-		" changelog.\n\n" +
-		"```template\n" +
-		template +
-		"```\n\n" +
-		"```commit_messages"
-
-	for _, message := range commitMessages {
-		prompt += message
-	}
-
-	prompt += "```\n\n"
-	prompt += "Some things I'd like you to pay attention to:\n" +
-		"* If there is a PR number for the commit, please put it at the end with a link to it.\n" +
-		"* Don't change the version numbers, repo name, or comments." +
-		"* Only send back the changelog, no extra commentary"
-
-	completion, err := client.Chat.Completions.New(context.Background(), openai.ChatCompletionNewParams{
-		Messages: openai.F([]openai.ChatCompletionMessageParamUnion{
-			openai.UserMessage(prompt),
-		}),
-		Model: openai.F(openai.ChatModelGPT4o),
-	})
-	if err != nil {
-		return "", err
-	}
-
-	// ChatGPT returns everything with markdown formatting so we remove it.
-	lines := strings.Split(completion.Choices[0].Message.Content, "\n")
-	var cleanedLines []string
-	for _, line := range lines {
-		if strings.TrimSpace(line) != "```" {
-			cleanedLines = append(cleanedLines, line)
-		}
-	}
-
-	result := strings.Join(cleanedLines, "\n")
-
-	return result, nil
-}
-
 func removeFileComments(data []byte) []byte {
 	var newFile [][]byte
 	lines := bytes.Split(data, []byte("\n"))
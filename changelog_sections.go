@@ -0,0 +1,260 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/go-git/go-git/plumbing"
+)
+
+// changelogSection pairs a conventional-commit kind with the Keep-a-Changelog-style heading
+// used for it, in the order sections are rendered.
+type changelogSection struct {
+	kind    commitType
+	heading string
+}
+
+var changelogSections = []changelogSection{
+	{feat, "Features"},
+	{fix, "Bug Fixes"},
+	{perf, "Performance"},
+	{refactor, "Refactors"},
+	{revert, "Reverts"},
+	{docs, "Docs"},
+	{ci, "CI"},
+	{chore, "Chores"},
+	{other, "Other"},
+}
+
+// changelogCommitEntry is a single commit as exposed to the changelog template.
+type changelogCommitEntry struct {
+	Message string
+	Hash    string // abbreviated
+	URL     string // link to the commit on GitHub
+}
+
+// changelogScopeGroup is the commits for a single scope within a changelogSectionEntry;
+// Scope is empty for commits with no parenthesized scope, and is always rendered first.
+type changelogScopeGroup struct {
+	Scope   string
+	Commits []changelogCommitEntry
+}
+
+// changelogSectionEntry is a changelogSection with its matching commits, grouped by scope, ready
+// for the template to render.
+type changelogSectionEntry struct {
+	Heading string
+	Scopes  []changelogScopeGroup
+}
+
+// changelogTemplateData is what a --changelog-template template, or the defaultChangelogTemplate,
+// renders from.
+type changelogTemplateData struct {
+	OrgAndRepo       string
+	Version          string
+	Date             string
+	Breaking         []changelogCommitEntry
+	Sections         []changelogSectionEntry
+	MalformedCommits []string
+}
+
+// defaultChangelogTemplate mirrors the grouping renderChangelogSection has always produced,
+// with scope sub-headings, commit links, and skipped commits now folded in as a collapsible
+// block instead of editor-only comments.
+const defaultChangelogTemplate = `## v{{.Version}} ({{.Date}})
+{{- if .Breaking}}
+
+### Breaking Changes
+
+{{- range .Breaking}}
+* [{{.Hash}}]({{.URL}}) {{.Message}}
+{{- end}}
+{{- end}}
+{{- range .Sections}}
+
+### {{.Heading}}
+{{- range .Scopes}}
+{{- if .Scope}}
+
+#### {{.Scope}}
+{{- end}}
+{{- range .Commits}}
+* [{{.Hash}}]({{.URL}}) {{.Message}}
+{{- end}}
+{{- end}}
+{{- end}}
+{{- if .MalformedCommits}}
+
+<details>
+<summary>Skipped commits (did not match conventional-commit format)</summary>
+
+{{- range .MalformedCommits}}
+* {{.}}
+{{- end}}
+
+</details>
+{{- end}}
+`
+
+// renderChangelogSection builds the Markdown for a single release, grouping parsedCommits by
+// conventional-commit kind and scope, with breaking changes called out in their own section at
+// the top and malformedCommits folded into a collapsible block. templatePath, if non-empty, is a
+// user-supplied Go template file used instead of defaultChangelogTemplate. host and hostname (ex.
+// githubHost/"github.com" or gitlabHost/"gitlab.mycompany.com") determine the commit link format.
+func renderChangelogSection(host gitHost, hostname, orgAndRepo, version, date string, parsedCommits []commitInfo, malformedCommits []string, templatePath string) (string, error) {
+	tmpl, err := loadChangelogTemplate(templatePath)
+	if err != nil {
+		return "", err
+	}
+
+	data := buildChangelogTemplateData(host, hostname, orgAndRepo, version, date, parsedCommits, malformedCommits)
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("could not render changelog template: %w", err)
+	}
+
+	return b.String(), nil
+}
+
+// loadChangelogTemplate parses templatePath as the changelog's Go template, or
+// defaultChangelogTemplate if templatePath is empty.
+func loadChangelogTemplate(templatePath string) (*template.Template, error) {
+	if templatePath == "" {
+		return template.New("changelog").Parse(defaultChangelogTemplate)
+	}
+
+	contents, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read changelog template %q: %w", templatePath, err)
+	}
+
+	tmpl, err := template.New("changelog").Parse(string(contents))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse changelog template %q: %w", templatePath, err)
+	}
+
+	return tmpl, nil
+}
+
+// buildChangelogTemplateData groups parsedCommits by kind and then scope, and builds the
+// commit entries (abbreviated hash plus a link to the commit on its git host) the template
+// renders.
+func buildChangelogTemplateData(host gitHost, hostname, orgAndRepo, version, date string, parsedCommits []commitInfo, malformedCommits []string) changelogTemplateData {
+	var breaking, rest []commitInfo
+	for _, c := range parsedCommits {
+		if c.breaking {
+			breaking = append(breaking, c)
+			continue
+		}
+		rest = append(rest, c)
+	}
+
+	data := changelogTemplateData{
+		OrgAndRepo:       orgAndRepo,
+		Version:          version,
+		Date:             date,
+		Breaking:         commitEntries(host, hostname, orgAndRepo, breaking),
+		MalformedCommits: malformedCommits,
+	}
+
+	for _, section := range changelogSections {
+		kindCommits := commitsOfKind(rest, section.kind)
+		if len(kindCommits) == 0 {
+			continue
+		}
+
+		data.Sections = append(data.Sections, changelogSectionEntry{
+			Heading: section.heading,
+			Scopes:  scopeGroups(host, hostname, orgAndRepo, kindCommits),
+		})
+	}
+
+	return data
+}
+
+func commitsOfKind(commits []commitInfo, kind commitType) []commitInfo {
+	var matched []commitInfo
+	for _, c := range commits {
+		if c.kind == kind {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
+
+// scopeGroups buckets commits by their conventional-commit scope, with unscoped commits first
+// followed by scopes in alphabetical order.
+func scopeGroups(host gitHost, hostname, orgAndRepo string, commits []commitInfo) []changelogScopeGroup {
+	byScope := map[string][]commitInfo{}
+	for _, c := range commits {
+		byScope[c.scope] = append(byScope[c.scope], c)
+	}
+
+	var scopes []string
+	for scope := range byScope {
+		if scope == "" {
+			continue
+		}
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+
+	var groups []changelogScopeGroup
+	if unscoped, ok := byScope[""]; ok {
+		groups = append(groups, changelogScopeGroup{Commits: commitEntries(host, hostname, orgAndRepo, unscoped)})
+	}
+	for _, scope := range scopes {
+		groups = append(groups, changelogScopeGroup{Scope: scope, Commits: commitEntries(host, hostname, orgAndRepo, byScope[scope])})
+	}
+
+	return groups
+}
+
+func commitEntries(host gitHost, hostname, orgAndRepo string, commits []commitInfo) []changelogCommitEntry {
+	entries := make([]changelogCommitEntry, 0, len(commits))
+	for _, c := range commits {
+		hash := plumbing.Hash(c.commit.Hash)
+		entries = append(entries, changelogCommitEntry{
+			Message: getShortMessage(c.commit),
+			Hash:    getAbbreviatedHash(hash),
+			URL:     commitURL(host, hostname, orgAndRepo, hash),
+		})
+	}
+	return entries
+}
+
+// commitURL builds the link to a commit on host, ex. https://github.com/org/repo/commit/<hash>
+// for GitHub or https://gitlab.com/org/repo/-/commit/<hash> for GitLab. hostname is the actual
+// remote hostname (ex. "github.com" or a GitHub Enterprise/self-hosted GitLab instance), so links
+// still resolve correctly for self-hosted repositories.
+func commitURL(host gitHost, hostname, orgAndRepo string, hash plumbing.Hash) string {
+	if host == gitlabHost {
+		return fmt.Sprintf("https://%s/%s/-/commit/%s", hostname, orgAndRepo, hash.String())
+	}
+
+	return fmt.Sprintf("https://%s/%s/commit/%s", hostname, orgAndRepo, hash.String())
+}
+
+// prependChangelog writes section at the top of filename, preserving whatever content the
+// file already has below it. A missing file is treated as an empty changelog.
+func prependChangelog(filename, section string) error {
+	existing, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not read changelog file %q: %w", filename, err)
+	}
+
+	updated := section
+	if len(existing) > 0 {
+		updated += "\n" + string(existing)
+	}
+
+	if err := os.WriteFile(filename, []byte(updated), 0o644); err != nil {
+		return fmt.Errorf("could not write changelog file %q: %w", filename, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestRenderChangelogSection(t *testing.T) {
+	parsedCommits := []commitInfo{
+		{kind: feat, commit: &object.Commit{Message: "feat: add widget"}},
+		{kind: fix, commit: &object.Commit{Message: "fix: stop crash"}},
+		{kind: feat, commit: &object.Commit{Message: "feat: breaking rework"}, breaking: true},
+	}
+
+	result, err := renderChangelogSection(githubHost, "github.com", "clintjedwards/release", "1.2.0", "July 1, 2026", parsedCommits, []string{"not a conventional commit"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(result, "## v1.2.0 (July 1, 2026)") {
+		t.Errorf("missing version heading; got %q", result)
+	}
+	if !strings.Contains(result, "### Breaking Changes") {
+		t.Errorf("missing breaking changes section; got %q", result)
+	}
+	if !strings.Contains(result, "### Features") || !strings.Contains(result, "add widget") {
+		t.Errorf("missing features section; got %q", result)
+	}
+	if !strings.Contains(result, "### Bug Fixes") || !strings.Contains(result, "stop crash") {
+		t.Errorf("missing bug fixes section; got %q", result)
+	}
+	if !strings.Contains(result, "https://github.com/clintjedwards/release/commit/") {
+		t.Errorf("missing commit link; got %q", result)
+	}
+	if !strings.Contains(result, "<details>") || !strings.Contains(result, "not a conventional commit") {
+		t.Errorf("missing malformed commits details block; got %q", result)
+	}
+}
+
+func TestRenderChangelogSectionScoped(t *testing.T) {
+	parsedCommits := []commitInfo{
+		{kind: feat, scope: "api", commit: &object.Commit{Message: "feat(api): add endpoint"}},
+		{kind: feat, commit: &object.Commit{Message: "feat: add widget"}},
+	}
+
+	result, err := renderChangelogSection(githubHost, "github.com", "clintjedwards/release", "1.2.0", "July 1, 2026", parsedCommits, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(result, "#### api") {
+		t.Errorf("missing scope sub-heading; got %q", result)
+	}
+	if !strings.Contains(result, "add endpoint") || !strings.Contains(result, "add widget") {
+		t.Errorf("missing scoped or unscoped commit; got %q", result)
+	}
+}
+
+func TestRenderChangelogSectionGitlabCommitLinks(t *testing.T) {
+	parsedCommits := []commitInfo{
+		{kind: feat, commit: &object.Commit{Message: "feat: add widget"}},
+	}
+
+	result, err := renderChangelogSection(gitlabHost, "gitlab.com", "clintjedwards/release", "1.2.0", "July 1, 2026", parsedCommits, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(result, "https://gitlab.com/clintjedwards/release/-/commit/") {
+		t.Errorf("missing gitlab-style commit link; got %q", result)
+	}
+	if strings.Contains(result, "github.com") {
+		t.Errorf("gitlab changelog should not link to github.com; got %q", result)
+	}
+}
+
+func TestPrependChangelog(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "CHANGELOG.md")
+
+	if err := prependChangelog(filename, "## v1.0.0\n\n* first\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := prependChangelog(filename, "## v2.0.0\n\n* second\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Index(string(contents), "v2.0.0") > strings.Index(string(contents), "v1.0.0") {
+		t.Errorf("expected newer section to be prepended above the older one; got %q", string(contents))
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// dryRunAsset describes a single resolved asset in a dry-run release plan.
+type dryRunAsset struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// dryRunPayload is the would-be release plan rendered by --dry-run, ready for a human or a
+// downstream CI step to inspect before a non-dry-run invocation actually publishes it.
+type dryRunPayload struct {
+	Tag    string        `json:"tag"`
+	Title  string        `json:"title"`
+	Body   string        `json:"body"`
+	Assets []dryRunAsset `json:"assets"`
+}
+
+// buildDryRunPayload resolves assetPaths to their absolute paths and sizes and assembles the
+// release plan that would otherwise be sent to createGithubRelease.
+func buildDryRunPayload(r *Release, assetPaths []string) (dryRunPayload, error) {
+	assets := make([]dryRunAsset, 0, len(assetPaths))
+
+	for _, path := range assetPaths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return dryRunPayload{}, fmt.Errorf("could not resolve absolute path for asset %q: %w", path, err)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return dryRunPayload{}, fmt.Errorf("could not stat asset %q: %w", path, err)
+		}
+
+		assets = append(assets, dryRunAsset{Path: absPath, Size: info.Size()})
+	}
+
+	return dryRunPayload{
+		Tag:    "v" + r.Version,
+		Title:  "v" + r.Version,
+		Body:   string(r.Changelog),
+		Assets: assets,
+	}, nil
+}
+
+// writeDryRunPayload renders payload as JSON to output, or to stdout if output is empty.
+func writeDryRunPayload(payload dryRunPayload, output string) error {
+	b, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal dry-run release plan: %w", err)
+	}
+
+	if output == "" {
+		fmt.Println(string(b))
+		return nil
+	}
+
+	if err := os.WriteFile(output, b, 0o644); err != nil {
+		return fmt.Errorf("could not write dry-run release plan to %q: %w", output, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildDryRunPayload(t *testing.T) {
+	dir := t.TempDir()
+	assetPath := filepath.Join(dir, "binary")
+	if err := os.WriteFile(assetPath, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Release{Version: "1.2.3", Changelog: []byte("## v1.2.3\n")}
+
+	payload, err := buildDryRunPayload(r, []string{assetPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if payload.Tag != "v1.2.3" {
+		t.Errorf("wrong tag; want %q; got %q", "v1.2.3", payload.Tag)
+	}
+
+	if len(payload.Assets) != 1 {
+		t.Fatalf("wrong number of assets; want 1; got %d", len(payload.Assets))
+	}
+
+	if payload.Assets[0].Size != 5 {
+		t.Errorf("wrong asset size; want 5; got %d", payload.Assets[0].Size)
+	}
+
+	if !filepath.IsAbs(payload.Assets[0].Path) {
+		t.Errorf("expected resolved asset path to be absolute; got %q", payload.Assets[0].Path)
+	}
+}
@@ -16,9 +16,11 @@ type commitType string
 
 const (
 	ci       commitType = "ci"
+	chore    commitType = "chore"
 	docs     commitType = "docs"
 	feat     commitType = "feat"
 	fix      commitType = "fix"
+	perf     commitType = "perf"
 	refactor commitType = "refactor"
 	revert   commitType = "revert"
 	other    commitType = "other"
@@ -26,11 +28,14 @@ const (
 
 type commitInfo struct {
 	kind     commitType
+	scope    string // optional parenthesized scope, ex. "api" in "feat(api): ..."; empty if unscoped
 	commit   *object.Commit
 	breaking bool
 }
 
-var commitTypes = map[commitType]struct{}{ci: {}, docs: {}, feat: {}, fix: {}, refactor: {}, revert: {}, other: {}}
+var commitTypes = map[commitType]struct{}{
+	ci: {}, chore: {}, docs: {}, feat: {}, fix: {}, perf: {}, refactor: {}, revert: {}, other: {},
+}
 
 // Loosely follows conventional commits
 // https://github.com/conventional-changelog/commitlint/tree/master/%40commitlint/config-conventional
@@ -52,6 +57,10 @@ func parseConventionalCommits(commits []*object.Commit) ([]commitInfo, []string)
 	return parsedCommits, malformedCommits
 }
 
+// breakingChangeFooter is the conventional-commits footer that marks a commit as breaking even
+// when its header doesn't carry the "!" marker.
+const breakingChangeFooter = "BREAKING CHANGE:"
+
 // parseCommitToInfo returns the given commit as a commitInfo type
 func parseCommitToInfo(commit *object.Commit) (commitInfo, error) {
 	msgSplit := strings.SplitN(commit.Message, ":", 2)
@@ -59,12 +68,16 @@ func parseCommitToInfo(commit *object.Commit) (commitInfo, error) {
 		return commitInfo{}, fmt.Errorf("could not properly split commit")
 	}
 
-	commitTag := msgSplit[0]
-	lastchar := commitTag[len(commitTag)-1]
+	header := msgSplit[0]
 	breaking := false
-	if lastchar == '!' {
+	if strings.HasSuffix(header, "!") {
 		breaking = true
-		commitTag = commitTag[:len(commitTag)-1]
+		header = header[:len(header)-1]
+	}
+
+	commitTag, scope := header, ""
+	if open := strings.Index(header, "("); open != -1 && strings.HasSuffix(header, ")") {
+		commitTag, scope = header[:open], header[open+1:len(header)-1]
 	}
 
 	kind := commitType(commitTag)
@@ -72,49 +85,66 @@ func parseCommitToInfo(commit *object.Commit) (commitInfo, error) {
 		return commitInfo{}, fmt.Errorf("could not parse commit type; %s is not a valid type", kind)
 	}
 
+	if !breaking && strings.Contains(commit.Message, breakingChangeFooter) {
+		breaking = true
+	}
+
 	return commitInfo{
 		kind:     kind,
+		scope:    scope,
 		commit:   commit,
 		breaking: breaking,
 	}, nil
 }
 
+// getCommitsAfterLatestTag is a thin wrapper around commitsInRange for callers that want the
+// default range: everything between the latest semver tag reachable from HEAD and HEAD itself.
 func getCommitsAfterLatestTag(repo *git.Repository) (*plumbing.Reference, []*object.Commit, error) {
-	// Get all the tags
-	tagRefs, err := repo.Tags()
-	if err != nil {
-		return nil, nil, fmt.Errorf("could not retrieve tags: %w", err)
-	}
+	return commitsInRange(repo, "", "")
+}
 
-	// Store all tags in a slice
-	var tags []*plumbing.Reference
-	err = tagRefs.ForEach(func(t *plumbing.Reference) error {
-		if _, err := semver.NewVersion(t.Name().Short()); err == nil {
-			tags = append(tags, t)
-		}
-		return nil
-	})
-	if err != nil {
-		return nil, nil, fmt.Errorf("could not iterate over tags")
+// commitsInRange resolves fromRev and toRev to commits via repo.ResolveRevision and returns every
+// commit reachable from toRev down to (but not including) fromRev. toRev defaults to "HEAD" when
+// empty. fromRev defaults to the newest semver tag reachable via toRev's first-parent ancestry
+// (rather than the newest tag in the whole repo), and the matching tag reference is returned
+// alongside the commits so callers can still read a base version off of it; when fromRev is
+// given explicitly the returned reference is nil, since an arbitrary revision isn't necessarily a
+// tag. Resolving revisions directly, instead of requiring the latest tag to appear in a full
+// `git log` walk, means this also works against shallow clones, force-pushed history, and orphan
+// branches.
+func commitsInRange(repo *git.Repository, fromRev, toRev string) (*plumbing.Reference, []*object.Commit, error) {
+	if toRev == "" {
+		toRev = "HEAD"
 	}
 
-	// If there are no tags, return nil for latestTag and an empty commits list
-	if len(tags) == 0 {
-		return nil, []*object.Commit{}, nil
+	toHash, err := repo.ResolveRevision(plumbing.Revision(toRev))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not resolve --to revision %q: %w", toRev, err)
 	}
 
-	// Sort the tags by SemVer
-	sort.Slice(tags, func(i, j int) bool {
-		v1, _ := semver.NewVersion(tags[i].Name().Short())
-		v2, _ := semver.NewVersion(tags[j].Name().Short())
-		return v1.LessThan(v2)
-	})
+	var latestTag *plumbing.Reference
+	var fromHash *plumbing.Hash
 
-	// Get the latest tag
-	latestTag := tags[len(tags)-1]
+	if fromRev != "" {
+		resolved, err := repo.ResolveRevision(plumbing.Revision(fromRev))
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not resolve --from revision %q: %w", fromRev, err)
+		}
+		fromHash = resolved
+	} else {
+		latestTag, err = latestSemverTagReachableFrom(repo, *toHash)
+		if err != nil {
+			return nil, nil, err
+		}
+		if latestTag == nil {
+			// No previous release to diff against; nothing is "after" it yet.
+			return nil, []*object.Commit{}, nil
+		}
+		h := latestTag.Hash()
+		fromHash = &h
+	}
 
-	// Get all commits
-	cIter, err := repo.Log(&git.LogOptions{})
+	cIter, err := repo.Log(&git.LogOptions{From: *toHash})
 	if err != nil {
 		return nil, nil, fmt.Errorf("could not retrieve commits: %w", err)
 	}
@@ -122,9 +152,8 @@ func getCommitsAfterLatestTag(repo *git.Repository) (*plumbing.Reference, []*obj
 	var commits []*object.Commit
 	found := false
 
-	// Get all commits after the latest tag
 	err = cIter.ForEach(func(c *object.Commit) error {
-		if c.Hash.String() == latestTag.Hash().String() {
+		if c.Hash == *fromHash {
 			found = true
 			return storer.ErrStop
 		}
@@ -132,14 +161,68 @@ func getCommitsAfterLatestTag(repo *git.Repository) (*plumbing.Reference, []*obj
 		commits = append(commits, c)
 		return nil
 	})
-
 	if err != nil && err != storer.ErrStop {
 		return nil, nil, fmt.Errorf("error iterating through commits: %w", err)
 	}
 
 	if !found {
-		return nil, nil, fmt.Errorf("latest tag not found in commit history")
+		return nil, nil, fmt.Errorf("--from revision %q not found in the history of --to %q", fromRev, toRev)
 	}
 
 	return latestTag, commits, nil
 }
+
+// latestSemverTagReachableFrom walks toHash's first-parent ancestry and returns the newest
+// semver-parseable tag whose commit appears in that ancestry, or nil if none do.
+func latestSemverTagReachableFrom(repo *git.Repository, toHash plumbing.Hash) (*plumbing.Reference, error) {
+	ancestry := map[plumbing.Hash]struct{}{}
+
+	commit, err := repo.CommitObject(toHash)
+	if err != nil {
+		return nil, fmt.Errorf("could not load commit %s: %w", toHash, err)
+	}
+
+	for {
+		ancestry[commit.Hash] = struct{}{}
+
+		if commit.NumParents() == 0 {
+			break
+		}
+
+		commit, err = commit.Parent(0)
+		if err != nil {
+			return nil, fmt.Errorf("could not walk first-parent ancestry: %w", err)
+		}
+	}
+
+	tagRefs, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve tags: %w", err)
+	}
+
+	var tags []*plumbing.Reference
+	err = tagRefs.ForEach(func(t *plumbing.Reference) error {
+		if _, ok := ancestry[t.Hash()]; !ok {
+			return nil
+		}
+		if _, err := semver.NewVersion(t.Name().Short()); err == nil {
+			tags = append(tags, t)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not iterate over tags")
+	}
+
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		v1, _ := semver.NewVersion(tags[i].Name().Short())
+		v2, _ := semver.NewVersion(tags[j].Name().Short())
+		return v1.LessThan(v2)
+	})
+
+	return tags[len(tags)-1], nil
+}
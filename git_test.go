@@ -81,6 +81,38 @@ func TestParseCommitToInfo(t *testing.T) {
 				breaking: true,
 			},
 		},
+		"scoped": {
+			commit: &object.Commit{
+				Message: "feat(api): test message",
+			},
+			expected: commitInfo{
+				kind:     feat,
+				scope:    "api",
+				commit:   nil,
+				breaking: false,
+			},
+		},
+		"scoped and breaking": {
+			commit: &object.Commit{
+				Message: "feat(api)!: test message",
+			},
+			expected: commitInfo{
+				kind:     feat,
+				scope:    "api",
+				commit:   nil,
+				breaking: true,
+			},
+		},
+		"breaking change footer": {
+			commit: &object.Commit{
+				Message: "feat: test message\n\nBREAKING CHANGE: this changes everything",
+			},
+			expected: commitInfo{
+				kind:     feat,
+				commit:   nil,
+				breaking: true,
+			},
+		},
 	}
 
 	for name, tc := range tests {
@@ -98,6 +130,10 @@ func TestParseCommitToInfo(t *testing.T) {
 				t.Errorf("parsing failure for message %q; unexpected kind; want %q; got %q", tc.commit.Message, tc.expected.kind, result.kind)
 			}
 
+			if result.scope != tc.expected.scope {
+				t.Errorf("parsing failure for message %q; unexpected scope; want %q; got %q", tc.commit.Message, tc.expected.scope, result.scope)
+			}
+
 		})
 	}
 }
@@ -4,18 +4,16 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/clintjedwards/polyfmt/v2"
-	"github.com/go-git/go-git/plumbing/storer"
-	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
-	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/google/go-github/github"
 	"github.com/mitchellh/go-homedir"
 	"golang.org/x/oauth2"
@@ -63,23 +61,16 @@ func newRelease(version, repository string) (*Release, error) {
 }
 
 // createGithubRelease cuts a new release, tags the current commit with semver, and uploads the changelog as a description
-func (r *Release) createGithubRelease(pfmt polyfmt.Formatter, tokenFile string, assetPaths ...string) error {
+func (r *Release) createGithubRelease(pfmt polyfmt.Formatter, tokenFile, baseURL string, assetPaths ...string) error {
 	pfmt.Print("Creating release")
 
 	pfmt.Print("Retrieving Github token")
-	token, err := getGithubToken(tokenFile)
+	client, err := newGithubClient(tokenFile, baseURL)
 	if err != nil {
-		pfmt.Err(fmt.Sprintf("Could not retrieve Github token from file %q; %v", tokenFile, err))
-		return fmt.Errorf("could not get github token from file %q: %w", tokenFile, err)
+		pfmt.Err(fmt.Sprintf("%v", err))
+		return err
 	}
 
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	tc := oauth2.NewClient(context.Background(), ts)
-
-	client := github.NewClient(tc)
-
 	release := &github.RepositoryRelease{
 		TagName: github.String("v" + r.Version),
 		Name:    github.String("v" + r.Version),
@@ -184,72 +175,132 @@ func parseGithubURL(githubURL string) (username, projectName string, err error)
 	return splitURL[0], splitURL[1], nil
 }
 
-func getCommitsAfterLatestTag(repo *git.Repository) (*plumbing.Reference, []*object.Commit, error) {
-	// Get all the tags
-	tagRefs, err := repo.Tags()
+func getSemverFromTag(ref *plumbing.Reference) string {
+	index := strings.LastIndex(ref.String(), "/")
+	return ref.String()[index+1:]
+}
+
+// githubBaseURLEnv overrides the GitHub API base/upload URL, for talking to a GitHub Enterprise
+// Server instance instead of public github.com.
+const githubBaseURLEnv string = "GITHUB_BASE_URL"
+
+// newGithubClient builds an authenticated github.Client from the token loaded out of tokenFile.
+// If baseURL is empty, it falls back to the GITHUB_BASE_URL environment variable and then the
+// release config file before defaulting to public github.com; otherwise a github.NewClient is
+// used. A non-empty base URL (from whichever source) builds a GitHub Enterprise Server client
+// via github.NewEnterpriseClient instead, using the same URL for both the API and upload hosts.
+func newGithubClient(tokenFile, baseURL string) (*github.Client, error) {
+	token, err := getGithubToken(tokenFile)
 	if err != nil {
-		return nil, nil, fmt.Errorf("could not retrieve tags: %w", err)
+		return nil, fmt.Errorf("could not get github token from file %q: %w", tokenFile, err)
 	}
 
-	// Store all tags in a slice
-	var tags []*plumbing.Reference
-	err = tagRefs.ForEach(func(t *plumbing.Reference) error {
-		if _, err := semver.NewVersion(t.Name().Short()); err == nil {
-			tags = append(tags, t)
-		}
-		return nil
-	})
-	if err != nil {
-		return nil, nil, fmt.Errorf("could not iterate over tags")
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+	tc := oauth2.NewClient(context.Background(), ts)
+
+	if baseURL == "" {
+		baseURL = resolveGithubBaseURL()
 	}
 
-	// If there are no tags, return nil for latestTag and an empty commits list
-	if len(tags) == 0 {
-		return nil, []*object.Commit{}, nil
+	if baseURL == "" {
+		return github.NewClient(tc), nil
 	}
 
-	// Sort the tags by SemVer
-	sort.Slice(tags, func(i, j int) bool {
-		v1, _ := semver.NewVersion(tags[i].Name().Short())
-		v2, _ := semver.NewVersion(tags[j].Name().Short())
-		return v1.LessThan(v2)
-	})
+	client, err := github.NewEnterpriseClient(baseURL, baseURL, tc)
+	if err != nil {
+		return nil, fmt.Errorf("could not build github enterprise client for %q: %w", baseURL, err)
+	}
 
-	// Get the latest tag
-	latestTag := tags[len(tags)-1]
+	return client, nil
+}
 
-	// Get all commits
-	cIter, err := repo.Log(&git.LogOptions{})
+// resolveGithubBaseURL checks GITHUB_BASE_URL and then the release config file for a GitHub
+// Enterprise Server base URL, returning "" (public github.com) if neither is set.
+func resolveGithubBaseURL() string {
+	if baseURL := os.Getenv(githubBaseURLEnv); baseURL != "" {
+		return baseURL
+	}
+
+	cfg, err := loadReleaseConfig(releaseConfigFileName)
 	if err != nil {
-		return nil, nil, fmt.Errorf("could not retrieve commits: %w", err)
+		return ""
 	}
 
-	var commits []*object.Commit
-	found := false
+	return cfg.GithubBaseURL
+}
 
-	// Get all commits after the latest tag
-	err = cIter.ForEach(func(c *object.Commit) error {
-		if c.Hash.String() == latestTag.Hash().String() {
-			found = true
-			return storer.ErrStop
-		}
+// deleteGithubRelease deletes release and its backing tag, used by --overwrite to clear the way
+// for createGithubRelease to recreate a release for the same version.
+func (r *Release) deleteGithubRelease(client *github.Client, release *github.RepositoryRelease) error {
+	if _, err := client.Repositories.DeleteRelease(context.Background(), r.Organization, r.Repository, release.GetID()); err != nil {
+		return fmt.Errorf("could not delete release %q: %w", release.GetTagName(), err)
+	}
+
+	if _, err := client.Git.DeleteRef(context.Background(), r.Organization, r.Repository, "tags/"+release.GetTagName()); err != nil {
+		return fmt.Errorf("could not delete tag %q: %w", release.GetTagName(), err)
+	}
 
-		commits = append(commits, c)
+	return nil
+}
+
+// overwriteExistingGithubRelease deletes org/repo's release for newRelease.Version, along with
+// its backing tag (both remote and, if present, local), if one already exists. It's a no-op if
+// no release for that exact version exists yet, so --overwrite only ever clears the way for a
+// re-run of the exact same version.
+func overwriteExistingGithubRelease(newRelease *Release, tokenFile, baseURL string, repository *git.Repository, pfmt polyfmt.Formatter) error {
+	client, err := newGithubClient(tokenFile, baseURL)
+	if err != nil {
+		return err
+	}
+
+	pfmt.Print("Checking for an existing release to overwrite")
+	existing, err := newRelease.getExistingGithubRelease(client)
+	if err != nil {
+		return fmt.Errorf("could not look up existing release: %w", err)
+	}
+
+	if existing == nil {
 		return nil
-	})
+	}
+
+	pfmt.Print(fmt.Sprintf("Deleting existing release %q", existing.GetTagName()))
+	if err := newRelease.deleteGithubRelease(client, existing); err != nil {
+		return err
+	}
 
-	if err != nil && err != storer.ErrStop {
-		return nil, nil, fmt.Errorf("error iterating through commits: %w", err)
+	if err := repository.DeleteTag(existing.GetTagName()); err != nil && err != git.ErrTagNotFound {
+		return fmt.Errorf("could not delete local tag %q: %w", existing.GetTagName(), err)
 	}
 
-	if !found {
-		return nil, nil, fmt.Errorf("latest tag not found in commit history")
+	pfmt.Success(fmt.Sprintf("Deleted existing release %q", existing.GetTagName()))
+	return nil
+}
+
+// getExistingGithubRelease looks up the release tagged "v"+version, if one already exists.
+// Returns a nil release (not an error) when none is found, so callers like the release workflow
+// can treat "already created" as success instead of re-creating it.
+func (r *Release) getExistingGithubRelease(client *github.Client) (*github.RepositoryRelease, error) {
+	release, resp, err := client.Repositories.GetReleaseByTag(context.Background(), r.Organization, r.Repository, "v"+r.Version)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
 	}
 
-	return latestTag, commits, nil
+	return release, nil
 }
 
-func getSemverFromTag(ref *plumbing.Reference) string {
-	index := strings.LastIndex(ref.String(), "/")
-	return ref.String()[index+1:]
+// uploadAssetIfMissing uploads path to releaseID unless an asset with the same filename is
+// already attached to the release, which makes re-running a failed upload step idempotent.
+func (r *Release) uploadAssetIfMissing(path string, release *github.RepositoryRelease, client *github.Client) error {
+	filename := filepath.Base(path)
+
+	for _, asset := range release.Assets {
+		if asset.GetName() == filename {
+			return nil
+		}
+	}
+
+	return r.uploadAsset(path, release.GetID(), client)
 }
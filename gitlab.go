@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/clintjedwards/polyfmt/v2"
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	gitlabTokenEnv      string = "GITLAB_TOKEN"
+	gitlabTokenFileName string = ".gitlab_token"
+	defaultGitlabAPIURL string = "https://gitlab.com/api/v4"
+)
+
+// gitlabPublisher implements ReleasePublisher for gitlab.com and self-hosted GitLab instances,
+// the latter reached via apiURL (--api-url).
+type gitlabPublisher struct {
+	apiURL string
+}
+
+// gitlabReleaseLink is a single entry in a GitLab release's assets.links array.
+type gitlabReleaseLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type gitlabReleaseAssets struct {
+	Links []gitlabReleaseLink `json:"links"`
+}
+
+// gitlabReleaseRequest is the body of a POST to the GitLab Releases API.
+type gitlabReleaseRequest struct {
+	TagName     string               `json:"tag_name"`
+	Description string               `json:"description"`
+	Assets      *gitlabReleaseAssets `json:"assets,omitempty"`
+}
+
+func (p gitlabPublisher) Publish(r *Release, pfmt polyfmt.Formatter, tokenFile string, assetPaths ...string) error {
+	pfmt.Print("Creating release")
+
+	pfmt.Print("Retrieving Gitlab token")
+	token, err := getGitlabToken(tokenFile)
+	if err != nil {
+		pfmt.Err(fmt.Sprintf("Could not retrieve Gitlab token from file %q; %v", tokenFile, err))
+		return fmt.Errorf("could not get gitlab token from file %q: %w", tokenFile, err)
+	}
+
+	apiURL := p.apiURL
+	if apiURL == "" {
+		apiURL = defaultGitlabAPIURL
+	}
+
+	projectPath := url.PathEscape(r.OrgAndRepo)
+	tagName := "v" + r.Version
+
+	links := []gitlabReleaseLink{}
+	if len(assetPaths) > 0 {
+		pfmt.Print("Uploading assets")
+		for _, assetPath := range assetPaths {
+			pfmt.Print(fmt.Sprintf("Uploading asset: %q", assetPath))
+
+			link, err := uploadGitlabAsset(apiURL, projectPath, token, tagName, assetPath)
+			if err != nil {
+				pfmt.Err(fmt.Sprintf("Could not upload asset %q; %v", assetPath, err))
+				continue
+			}
+
+			links = append(links, link)
+			pfmt.Success(fmt.Sprintf("Uploaded asset: %q", assetPath))
+		}
+	}
+
+	release := gitlabReleaseRequest{
+		TagName:     tagName,
+		Description: string(r.Changelog),
+	}
+	if len(links) > 0 {
+		release.Assets = &gitlabReleaseAssets{Links: links}
+	}
+
+	body, err := json.Marshal(release)
+	if err != nil {
+		return fmt.Errorf("could not marshal release payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/projects/%s/releases", apiURL, projectPath), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not build release request: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", token)
+	req.Header.Set("Content-Type", "application/json")
+
+	pfmt.Print("Creating release")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		pfmt.Err(fmt.Sprintf("Could not create release; %v", err))
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		err := fmt.Errorf("gitlab returned status %d: %s", resp.StatusCode, string(respBody))
+		pfmt.Err(fmt.Sprintf("Could not create release; %v", err))
+		return err
+	}
+
+	pfmt.Success("Successfully created release!")
+
+	return nil
+}
+
+// uploadGitlabAsset pushes an asset to the project's generic package registry and returns the
+// release link that points at it, since GitLab releases don't accept direct binary uploads the
+// way GitHub releases do.
+func uploadGitlabAsset(apiURL, projectPath, token, version, path string) (gitlabReleaseLink, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return gitlabReleaseLink{}, fmt.Errorf("could not find asset file: %s; %w", path, err)
+	}
+	defer f.Close()
+
+	filename := filepath.Base(path)
+	uploadURL := fmt.Sprintf("%s/projects/%s/packages/generic/release-assets/%s/%s", apiURL, projectPath, version, filename)
+
+	req, err := http.NewRequest(http.MethodPut, uploadURL, f)
+	if err != nil {
+		return gitlabReleaseLink{}, err
+	}
+	req.Header.Set("PRIVATE-TOKEN", token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return gitlabReleaseLink{}, fmt.Errorf("could not upload asset file: %s; %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return gitlabReleaseLink{}, fmt.Errorf("gitlab returned status %d uploading %s: %s", resp.StatusCode, path, string(respBody))
+	}
+
+	return gitlabReleaseLink{Name: filename, URL: uploadURL}, nil
+}
+
+// getGitlabToken attempts to load a gitlab token and returns an error if none exists, checking
+// the GITLAB_TOKEN environment variable first, then tokenFile, then ~/.gitlab_token.
+func getGitlabToken(tokenFile string) (token string, err error) {
+	token = os.Getenv(gitlabTokenEnv)
+	if token != "" {
+		return token, nil
+	}
+
+	if tokenFile == "" {
+		home, err := homedir.Dir()
+		if err != nil {
+			return "", fmt.Errorf("could not get user home dir: %w", err)
+		}
+
+		tokenFile = fmt.Sprintf("%s/%s", home, gitlabTokenFileName)
+	}
+
+	rawToken, err := readTokenFile(tokenFile)
+	if err != nil {
+		return "", err
+	}
+
+	return string(rawToken), nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/clintjedwards/polyfmt/v2"
+	"github.com/go-git/go-git/v5"
+)
+
+// gitHost identifies which hosting provider a repository's origin remote points at, and
+// therefore which ReleasePublisher implementation should cut the release.
+type gitHost string
+
+const (
+	githubHost gitHost = "github"
+	gitlabHost gitHost = "gitlab"
+)
+
+// ReleasePublisher cuts a release for an already-tagged commit against whatever git hosting
+// provider backs the repository. release() in main.go picks the implementation based on the
+// detected (or --host-overridden) gitHost.
+type ReleasePublisher interface {
+	Publish(r *Release, pfmt polyfmt.Formatter, tokenFile string, assetPaths ...string) error
+}
+
+// newReleasePublisher returns the ReleasePublisher for host, configured to talk to apiURL if
+// the host is self-hosted (apiURL is ignored by hosts that don't need it yet).
+func newReleasePublisher(host gitHost, apiURL string) (ReleasePublisher, error) {
+	switch host {
+	case githubHost:
+		return githubPublisher{baseURL: apiURL}, nil
+	case gitlabHost:
+		return gitlabPublisher{apiURL: apiURL}, nil
+	default:
+		return nil, fmt.Errorf("unknown git host %q", host)
+	}
+}
+
+// detectGitHost guesses the gitHost from a remote's hostname, ex. "github.com" or
+// "gitlab.mycompany.com". Returns false if the hostname doesn't look like either provider,
+// which is the signal callers use to fall back to asking the user for --host.
+func detectGitHost(hostname string) (gitHost, bool) {
+	switch {
+	case strings.Contains(hostname, "github"):
+		return githubHost, true
+	case strings.Contains(hostname, "gitlab"):
+		return gitlabHost, true
+	default:
+		return "", false
+	}
+}
+
+// getOrgAndRepo parses the origin remote's URL and returns its hostname (ex. "github.com")
+// along with the "org/repo" path, independent of which provider actually hosts it.
+func getOrgAndRepo(repo *git.Repository) (hostname, orgAndRepo string, err error) {
+	remoteConfig, err := repo.Remote("origin")
+	if err != nil {
+		return "", "", fmt.Errorf("could not get origin remote: %w", err)
+	}
+
+	remoteURL := remoteConfig.Config().URLs[0]
+	return parseRemoteURL(remoteURL)
+}
+
+// parseRemoteURL splits a git remote URL, SSH (git@host:org/repo.git) or HTTPS
+// (https://host/org/repo.git), into the host it points at and the "org/repo" path.
+func parseRemoteURL(remoteURL string) (hostname, orgAndRepo string, err error) {
+	if strings.HasPrefix(remoteURL, "git@") {
+		parts := strings.SplitN(strings.TrimSuffix(remoteURL, ".git"), ":", 2)
+		if len(parts) != 2 {
+			return "", "", fmt.Errorf("unexpected SSH URL format")
+		}
+		return strings.TrimPrefix(parts[0], "git@"), parts[1], nil
+	}
+
+	parsedURL, err := url.Parse(remoteURL)
+	if err != nil {
+		return "", "", fmt.Errorf("could not parse remote URL: %w", err)
+	}
+
+	pathParts := strings.Split(strings.Trim(strings.TrimSuffix(parsedURL.Path, ".git"), "/"), "/")
+	if len(pathParts) < 2 {
+		return "", "", fmt.Errorf("unexpected HTTPS URL format")
+	}
+
+	return parsedURL.Hostname(), strings.Join(pathParts[len(pathParts)-2:], "/"), nil
+}
+
+// githubPublisher implements ReleasePublisher for github.com and GitHub Enterprise
+// repositories, backed by the existing createGithubRelease flow. baseURL is empty for
+// public github.com, or a GitHub Enterprise Server API root otherwise.
+type githubPublisher struct {
+	baseURL string
+}
+
+func (p githubPublisher) Publish(r *Release, pfmt polyfmt.Formatter, tokenFile string, assetPaths ...string) error {
+	return r.createGithubRelease(pfmt, tokenFile, p.baseURL, assetPaths...)
+}
@@ -0,0 +1,58 @@
+package main
+
+import "testing"
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		remoteURL   string
+		wantHost    string
+		wantOrgRepo string
+	}{
+		{"github ssh", "git@github.com:clintjedwards/release.git", "github.com", "clintjedwards/release"},
+		{"github https", "https://github.com/clintjedwards/release.git", "github.com", "clintjedwards/release"},
+		{"gitlab ssh", "git@gitlab.com:myorg/myrepo.git", "gitlab.com", "myorg/myrepo"},
+		{"gitlab https", "https://gitlab.com/myorg/myrepo.git", "gitlab.com", "myorg/myrepo"},
+		{"self-hosted gitlab https", "https://gitlab.mycompany.com/myorg/myrepo.git", "gitlab.mycompany.com", "myorg/myrepo"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			host, orgAndRepo, err := parseRemoteURL(test.remoteURL)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if host != test.wantHost {
+				t.Errorf("wrong host; want %q; got %q", test.wantHost, host)
+			}
+			if orgAndRepo != test.wantOrgRepo {
+				t.Errorf("wrong org/repo; want %q; got %q", test.wantOrgRepo, orgAndRepo)
+			}
+		})
+	}
+}
+
+func TestDetectGitHost(t *testing.T) {
+	tests := []struct {
+		hostname string
+		want     gitHost
+		wantOK   bool
+	}{
+		{"github.com", githubHost, true},
+		{"github.mycompany.com", githubHost, true},
+		{"gitlab.com", gitlabHost, true},
+		{"gitlab.mycompany.com", gitlabHost, true},
+		{"bitbucket.org", "", false},
+	}
+
+	for _, test := range tests {
+		got, ok := detectGitHost(test.hostname)
+		if ok != test.wantOK {
+			t.Errorf("%q: wrong ok; want %v; got %v", test.hostname, test.wantOK, ok)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%q: wrong host; want %q; got %q", test.hostname, test.want, got)
+		}
+	}
+}
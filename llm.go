@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	anthropicoption "github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/mitchellh/go-homedir"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+// llmProvider identifies which backend ChangelogGenerator talks to.
+type llmProvider string
+
+const (
+	openaiProvider      llmProvider = "openai"
+	anthropicProvider   llmProvider = "anthropic"
+	ollamaProvider      llmProvider = "ollama"
+	azureOpenAIProvider llmProvider = "azure-openai"
+)
+
+const (
+	defaultOpenAIModel     string = "gpt-4o"
+	defaultAnthropicModel  string = "claude-3-7-sonnet-latest"
+	defaultOllamaModel     string = "llama3"
+	defaultOllamaEndpoint  string = "http://localhost:11434/v1"
+	defaultAzureAPIVersion string = "2024-06-01"
+)
+
+// ChangelogGenerator rewrites a changelog template into final changelog text, using the
+// commit messages since the last release as context. Implementations wrap whatever LLM
+// backend the user has configured via --llm-provider.
+type ChangelogGenerator interface {
+	Generate(template string, commitMessages []string) (string, error)
+}
+
+// newChangelogGenerator builds the ChangelogGenerator for the requested provider, loading
+// whatever token/endpoint that provider needs.
+func newChangelogGenerator(provider llmProvider, model, endpoint, tokenFile string) (ChangelogGenerator, error) {
+	switch provider {
+	case openaiProvider:
+		token, err := getLLMToken(string(openaiProvider), tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not get openai token: %w", err)
+		}
+		if model == "" {
+			model = defaultOpenAIModel
+		}
+		return &openAIGenerator{token: token, model: model}, nil
+
+	case anthropicProvider:
+		token, err := getLLMToken(string(anthropicProvider), tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not get anthropic token: %w", err)
+		}
+		if model == "" {
+			model = defaultAnthropicModel
+		}
+		return &anthropicGenerator{token: token, model: model}, nil
+
+	case ollamaProvider:
+		// Ollama is usually a local, unauthenticated server, so a missing token is fine.
+		token, _ := getLLMToken(string(ollamaProvider), tokenFile)
+		if model == "" {
+			model = defaultOllamaModel
+		}
+		if endpoint == "" {
+			endpoint = defaultOllamaEndpoint
+		}
+		return &openAIGenerator{token: token, model: model, baseURL: endpoint}, nil
+
+	case azureOpenAIProvider:
+		token, err := getLLMToken(string(azureOpenAIProvider), tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not get azure openai token: %w", err)
+		}
+		if endpoint == "" {
+			return nil, fmt.Errorf("--llm-endpoint is required for the azure-openai provider")
+		}
+		if model == "" {
+			return nil, fmt.Errorf("--llm-model (the Azure deployment name) is required for the azure-openai provider")
+		}
+		return &azureOpenAIGenerator{token: token, deployment: model, endpoint: endpoint}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown llm provider %q", provider)
+	}
+}
+
+// llmTokenEnvVar and llmTokenFileName mirror the lookup locations getGithubToken uses,
+// parameterized by provider name. ex. provider "anthropic" -> env var ANTHROPIC_TOKEN,
+// file ~/.anthropic_token.
+func llmTokenEnvVar(provider string) string {
+	return strings.ToUpper(provider) + "_TOKEN"
+}
+
+func llmTokenFileName(provider string) string {
+	return "." + provider + "_token"
+}
+
+// getLLMToken loads an API token for the given LLM provider, checking the provider's
+// <PROVIDER>_TOKEN environment variable first, then tokenFile, then ~/.<provider>_token.
+func getLLMToken(provider, tokenFile string) (string, error) {
+	token := os.Getenv(llmTokenEnvVar(provider))
+	if token != "" {
+		return token, nil
+	}
+
+	if tokenFile == "" {
+		home, err := homedir.Dir()
+		if err != nil {
+			return "", fmt.Errorf("could not get user home dir: %w", err)
+		}
+
+		tokenFile = fmt.Sprintf("%s/%s", home, llmTokenFileName(provider))
+	}
+
+	rawToken, err := readTokenFile(tokenFile)
+	if err != nil {
+		return "", err
+	}
+
+	return string(rawToken), nil
+}
+
+func readTokenFile(filename string) ([]byte, error) {
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("could not find token file: %s; %w", filename, err)
+	}
+	if len(contents) == 0 {
+		return nil, fmt.Errorf("token file empty: %s", filename)
+	}
+
+	return bytes.TrimSpace(contents), nil
+}
+
+// buildChangelogPrompt constructs the provider-agnostic prompt shared by every
+// ChangelogGenerator implementation.
+func buildChangelogPrompt(tmpl string, commitMessages []string) string {
+	prompt := "I want you to help me write a changelog. Below I will define the template I want you to follow" +
+		" and I'll pass you the commit messages you should use to change and fill in the template and give me a useable " +
+		" changelog.\n\n" +
+		"```template\n" +
+		tmpl +
+		"```\n\n" +
+		"```commit_messages\n"
+
+	for _, message := range commitMessages {
+		prompt += message
+	}
+
+	prompt += "```\n\n"
+	prompt += "Some things I'd like you to pay attention to:\n" +
+		"* If there is a PR number for the commit, please put it at the end with a link to it.\n" +
+		"* Don't change the version numbers, repo name, or comments.\n" +
+		"* Only send back the changelog, no extra commentary"
+
+	return prompt
+}
+
+// stripMarkdownFences removes the ```-fenced lines LLMs tend to wrap responses in, since the
+// caller wants the raw changelog body.
+func stripMarkdownFences(text string) string {
+	lines := strings.Split(text, "\n")
+	var cleanedLines []string
+	for _, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), "```") {
+			cleanedLines = append(cleanedLines, line)
+		}
+	}
+
+	return strings.Join(cleanedLines, "\n")
+}
+
+// openAIGenerator talks to OpenAI's chat completions API. With a baseURL set it also serves
+// as the Ollama implementation, since Ollama exposes an OpenAI-compatible endpoint.
+type openAIGenerator struct {
+	token   string
+	model   string
+	baseURL string
+}
+
+func (g *openAIGenerator) Generate(tmpl string, commitMessages []string) (string, error) {
+	opts := []option.RequestOption{option.WithAPIKey(g.token)}
+	if g.baseURL != "" {
+		opts = append(opts, option.WithBaseURL(g.baseURL))
+	}
+
+	client := openai.NewClient(opts...)
+
+	completion, err := client.Chat.Completions.New(context.Background(), openai.ChatCompletionNewParams{
+		Messages: []openai.ChatCompletionMessageParamUnion{
+			openai.UserMessage(buildChangelogPrompt(tmpl, commitMessages)),
+		},
+		Model: g.model,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return stripMarkdownFences(completion.Choices[0].Message.Content), nil
+}
+
+// anthropicGenerator talks to the Anthropic Messages API.
+type anthropicGenerator struct {
+	token string
+	model string
+}
+
+func (g *anthropicGenerator) Generate(tmpl string, commitMessages []string) (string, error) {
+	client := anthropic.NewClient(anthropicoption.WithAPIKey(g.token))
+
+	message, err := client.Messages.New(context.Background(), anthropic.MessageNewParams{
+		Model:     anthropic.Model(g.model),
+		MaxTokens: 4096,
+		Messages: []anthropic.MessageParam{
+			anthropic.NewUserMessage(anthropic.NewTextBlock(buildChangelogPrompt(tmpl, commitMessages))),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	for _, block := range message.Content {
+		text.WriteString(block.Text)
+	}
+
+	return stripMarkdownFences(text.String()), nil
+}
+
+// azureOpenAIGenerator talks to an Azure OpenAI deployment, which uses the OpenAI-compatible
+// API shape but is addressed by endpoint + deployment name rather than a model ID.
+type azureOpenAIGenerator struct {
+	token      string
+	deployment string
+	endpoint   string
+}
+
+func (g *azureOpenAIGenerator) Generate(tmpl string, commitMessages []string) (string, error) {
+	baseURL := fmt.Sprintf("%s/openai/deployments/%s", strings.TrimSuffix(g.endpoint, "/"), g.deployment)
+
+	client := openai.NewClient(
+		option.WithBaseURL(baseURL),
+		option.WithAPIKey(g.token),
+		option.WithQuery("api-version", defaultAzureAPIVersion),
+	)
+
+	completion, err := client.Chat.Completions.New(context.Background(), openai.ChatCompletionNewParams{
+		Messages: []openai.ChatCompletionMessageParamUnion{
+			openai.UserMessage(buildChangelogPrompt(tmpl, commitMessages)),
+		},
+		Model: g.deployment,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return stripMarkdownFences(completion.Choices[0].Message.Content), nil
+}
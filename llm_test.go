@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildChangelogPrompt(t *testing.T) {
+	prompt := buildChangelogPrompt("## v1.0.0\n", []string{"abc123: feat: add widget\n", "def456: fix: stop crash\n"})
+
+	for _, want := range []string{
+		"```template", "## v1.0.0", "```commit_messages",
+		"abc123: feat: add widget", "def456: fix: stop crash",
+		"Only send back the changelog, no extra commentary",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q; got %q", want, prompt)
+		}
+	}
+}
+
+func TestStripMarkdownFences(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"no fences", "## v1.0.0\n\n* first change", "## v1.0.0\n\n* first change"},
+		{"wrapped in fences", "```markdown\n## v1.0.0\n\n* first change\n```", "## v1.0.0\n\n* first change"},
+		{"indented fence", "## v1.0.0\n  ```\n* first change", "## v1.0.0\n* first change"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := stripMarkdownFences(test.in)
+			if got != test.want {
+				t.Errorf("want %q; got %q", test.want, got)
+			}
+		})
+	}
+}
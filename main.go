@@ -3,7 +3,6 @@ package main
 import (
 	"bytes"
 	"fmt"
-	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -24,8 +23,11 @@ var appVersion = "0.0.dev_000000"
 // provides the interface for the main command which is simply 'release'.
 var rootCmd = &cobra.Command{
 	Use:   "release",
-	Short: "Helper for simple github releases",
-	Long: `Helper for simple github releases.
+	Short: "Helper for cutting GitHub and GitLab releases",
+	Long: `Helper for cutting GitHub and GitLab releases.
+
+Supports GitHub Enterprise Server, signed tags, LLM-generated changelogs, and
+a resumable run/resume/status workflow, in addition to the interactive flow.
 
 Tool will confirm before pushing any changes.`,
 	Version: " ", // We leave this added but empty so that the rootcmd will supply the -v flag
@@ -64,13 +66,79 @@ func release(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		panic(err)
 	}
-	useChatGPT, err := cmd.Flags().GetBool("chatgpt")
+	gitlabTokenFile, err := cmd.Flags().GetString("gitlab_token_file")
 	if err != nil {
 		panic(err)
 	}
-
-	// We don't yet have the appropriate functionality for the llm token file yet.
-	_, err = cmd.Flags().GetString("chatgpt_token_file")
+	hostFlag, err := cmd.Flags().GetString("host")
+	if err != nil {
+		panic(err)
+	}
+	apiURL, err := cmd.Flags().GetString("api-url")
+	if err != nil {
+		panic(err)
+	}
+	useLLM, err := cmd.Flags().GetBool("llm")
+	if err != nil {
+		panic(err)
+	}
+	llmProviderFlag, err := cmd.Flags().GetString("llm-provider")
+	if err != nil {
+		panic(err)
+	}
+	llmModel, err := cmd.Flags().GetString("llm-model")
+	if err != nil {
+		panic(err)
+	}
+	llmEndpoint, err := cmd.Flags().GetString("llm-endpoint")
+	if err != nil {
+		panic(err)
+	}
+	llmTokenFile, err := cmd.Flags().GetString("llm_token_file")
+	if err != nil {
+		panic(err)
+	}
+	changelogFile, err := cmd.Flags().GetString("changelog-file")
+	if err != nil {
+		panic(err)
+	}
+	changelogTemplate, err := cmd.Flags().GetString("changelog-template")
+	if err != nil {
+		panic(err)
+	}
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		panic(err)
+	}
+	dryRunOutput, err := cmd.Flags().GetString("dry-run-output")
+	if err != nil {
+		panic(err)
+	}
+	sign, err := cmd.Flags().GetBool("sign")
+	if err != nil {
+		panic(err)
+	}
+	noSign, err := cmd.Flags().GetBool("no-sign")
+	if err != nil {
+		panic(err)
+	}
+	signingKey, err := cmd.Flags().GetString("signing-key")
+	if err != nil {
+		panic(err)
+	}
+	signerFlag, err := cmd.Flags().GetString("signer")
+	if err != nil {
+		panic(err)
+	}
+	overwrite, err := cmd.Flags().GetBool("overwrite")
+	if err != nil {
+		panic(err)
+	}
+	fromRev, err := cmd.Flags().GetString("from")
+	if err != nil {
+		panic(err)
+	}
+	toRev, err := cmd.Flags().GetString("to")
 	if err != nil {
 		panic(err)
 	}
@@ -89,17 +157,44 @@ func release(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	orgAndRepo, err := getOrgAndRepo(repository)
+	hostname, orgAndRepo, err := getOrgAndRepo(repository)
 	if err != nil {
 		pfmt.Err(fmt.Sprintf("Could not parse repository name; %v", err))
 		return err
 	}
 
-	latestTag, commits, err := getCommitsAfterLatestTag(repository)
+	host := gitHost(hostFlag)
+	if host == "" {
+		detected, ok := detectGitHost(hostname)
+		if !ok {
+			pfmt.Err(fmt.Sprintf("Could not determine git host from remote %q; pass --host github or --host gitlab", hostname))
+			return fmt.Errorf("could not determine git host from remote %q", hostname)
+		}
+		host = detected
+	}
+
+	publisher, err := newReleasePublisher(host, apiURL)
 	if err != nil {
+		pfmt.Err(fmt.Sprintf("%v", err))
+		return err
+	}
+
+	tokenFile := githubTokenFile
+	if host == gitlabHost {
+		tokenFile = gitlabTokenFile
+	}
+
+	latestTag, commits, err := commitsInRange(repository, fromRev, toRev)
+	if err != nil {
+		if fromRev != "" || toRev != "" {
+			pfmt.Err(fmt.Sprintf("Could not select commits for --from/--to range; %v", err))
+			return err
+		}
 		pfmt.Err(fmt.Sprintf("Could not find any previous releases; %v", err))
 	}
 
+	parsedCommits, malformedCommits := parseConventionalCommits(commits)
+
 	// If the user hasn't actually set the version flag then we need to determine what it is.
 	// We do this by prompting the user for the version, but before doing that taking a best
 	// guess on what it might be if we were able to glean a previous version from the proceeding
@@ -110,12 +205,17 @@ func release(cmd *cobra.Command, _ []string) error {
 
 		if latestTag != nil {
 			latestVersion = getSemverFromTag(latestTag)
+		}
 
-			// This should never fail, since we run the same command on the latestTag in the previous
-			// function.
-			latestSemver, _ := semver.NewVersion(latestVersion)
-			*latestSemver = latestSemver.IncMinor()
-			possibleNextVersion = latestSemver.String()
+		cfg, err := loadReleaseConfig(releaseConfigFileName)
+		if err != nil {
+			pfmt.Err(fmt.Sprintf("Could not load release config, falling back to defaults; %v", err))
+			cfg = defaultReleaseConfig()
+		}
+
+		next, err := nextVersion(latestVersion, parsedCommits, cfg)
+		if err == nil {
+			possibleNextVersion = next
 		}
 
 		if latestVersion != "" {
@@ -180,19 +280,22 @@ func release(cmd *cobra.Command, _ []string) error {
 
 	pfmt.Println(fmt.Sprintf("\nReleasing %s of %s", color.BlueString("v"+semverVersion), color.BlueString(orgAndRepo)))
 
-	shortCommitStrs := []string{}
-	for _, commit := range commits {
-		message := fmt.Sprintf("%s: %s", getAbbreviatedHash(plumbing.Hash(commit.Hash)), getShortMessage(commit))
-		shortCommitStrs = append(shortCommitStrs, message)
-	}
-
 	longCommitStrs := []string{}
 	for _, commit := range commits {
 		message := fmt.Sprintf("%s: %s", commit.Hash, commit.Message)
 		longCommitStrs = append(longCommitStrs, message)
 	}
 
-	cl, err := handleChangelog(newRelease.OrgAndRepo, newRelease.Version, newRelease.Date, shortCommitStrs, longCommitStrs, pfmt, useChatGPT)
+	var generator ChangelogGenerator
+	if useLLM {
+		generator, err = newChangelogGenerator(llmProvider(llmProviderFlag), llmModel, llmEndpoint, llmTokenFile)
+		if err != nil {
+			pfmt.Err(fmt.Sprintf("Could not set up %q changelog generator; %v", llmProviderFlag, err))
+			return err
+		}
+	}
+
+	cl, err := handleChangelog(host, hostname, newRelease.OrgAndRepo, newRelease.Version, newRelease.Date, parsedCommits, malformedCommits, longCommitStrs, pfmt, generator, changelogTemplate)
 	if err != nil {
 		pfmt.Err(fmt.Sprintf("%v", err))
 		return err
@@ -200,6 +303,68 @@ func release(cmd *cobra.Command, _ []string) error {
 
 	newRelease.Changelog = cl
 
+	if dryRun {
+		payload, err := buildDryRunPayload(newRelease, assetPaths)
+		if err != nil {
+			pfmt.Err(fmt.Sprintf("%v", err))
+			return err
+		}
+
+		if err := writeDryRunPayload(payload, dryRunOutput); err != nil {
+			pfmt.Err(fmt.Sprintf("%v", err))
+			return err
+		}
+
+		pfmt.Success("Wrote dry-run release plan")
+		return nil
+	}
+
+	var tagSign *tagSignConfig
+	signFingerprint := ""
+
+	signRelease := sign || signingKey != ""
+	if !cmd.Flag("sign").Changed && !noSign && signingKey == "" {
+		if key, ok := gitConfigSigningKey(repository); ok {
+			signRelease = true
+			signingKey = key
+		} else if gitConfigTagGpgSign(repository) {
+			signRelease = true
+		}
+	}
+	if noSign {
+		signRelease = false
+	}
+
+	if signRelease {
+		kind := gitConfigSignerKind(repository)
+		if signerFlag != "" {
+			kind = signerKind(signerFlag)
+		}
+		if kind != gpgSigner && kind != sshSigner {
+			pfmt.Err(fmt.Sprintf("Unknown --signer %q; accepted values are 'gpg', 'ssh'", signerFlag))
+			return fmt.Errorf("unknown signer %q", signerFlag)
+		}
+
+		if signingKey == "" {
+			pfmt.Err("Signing requested but no signing key was found; set user.signingkey in git config or pass --signing-key")
+			return fmt.Errorf("no signing key configured")
+		}
+
+		if kind == sshSigner {
+			tagSign = &tagSignConfig{Signer: sshSigner, SSHKeyPath: signingKey}
+			signFingerprint = signingKey
+		} else {
+			signEntity, err := loadSigningKey(signingKey, pfmt)
+			if err != nil {
+				pfmt.Err(fmt.Sprintf("Could not load signing key %q; %v", signingKey, err))
+				return err
+			}
+
+			tagSign = &tagSignConfig{Signer: gpgSigner, Entity: signEntity}
+			signFingerprint = signingKeyFingerprint(signEntity)
+		}
+	}
+
 	funcMap := template.FuncMap{
 		"blue":    color.BlueString,
 		"magenta": color.MagentaString,
@@ -211,6 +376,9 @@ Details:
 {{.Divider | magenta}} Repository: {{.Repository | blue}}
 {{.Divider | magenta}} Semver Version: {{.Semver | blue}}
 {{.Divider | magenta}} Release Date: {{.Date | blue}}
+{{- if .SigningFingerprint}}
+{{.Divider | magenta}} Signing Key: {{.SigningFingerprint | blue}}
+{{- end}}
 {{- if gt (len .Assets) 0}}
 {{.Divider | magenta}} Assets:
 {{- range .Assets}}
@@ -224,23 +392,25 @@ Details:
 	var tpl bytes.Buffer
 	tmpl := template.Must(template.New("").Funcs(funcMap).Parse(releaseDetails))
 	err = tmpl.Execute(&tpl, struct {
-		Divider          string
-		Organization     string
-		Repository       string
-		Semver           string
-		Date             string
-		Assets           []string
-		ChangelogDivider string
-		Changelog        string
+		Divider            string
+		Organization       string
+		Repository         string
+		Semver             string
+		Date               string
+		SigningFingerprint string
+		Assets             []string
+		ChangelogDivider   string
+		Changelog          string
 	}{
-		Divider:          "│",
-		Organization:     newRelease.Organization,
-		Repository:       newRelease.Repository,
-		Semver:           "v" + newRelease.Version,
-		Date:             newRelease.Date,
-		Assets:           assetPaths,
-		ChangelogDivider: "└─────┐",
-		Changelog:        string(newRelease.Changelog),
+		Divider:            "│",
+		Organization:       newRelease.Organization,
+		Repository:         newRelease.Repository,
+		Semver:             "v" + newRelease.Version,
+		Date:               newRelease.Date,
+		SigningFingerprint: signFingerprint,
+		Assets:             assetPaths,
+		ChangelogDivider:   "└─────┐",
+		Changelog:          string(newRelease.Changelog),
 	})
 	if err != nil {
 		return err
@@ -255,12 +425,35 @@ Details:
 		return nil
 	}
 
-	err = newRelease.createGithubRelease(pfmt, githubTokenFile, assetPaths...)
+	if overwrite {
+		if host != githubHost {
+			pfmt.Err("--overwrite is only supported for github releases")
+			return fmt.Errorf("--overwrite is only supported for github releases")
+		}
+
+		if err := overwriteExistingGithubRelease(newRelease, tokenFile, apiURL, repository, pfmt); err != nil {
+			pfmt.Err(fmt.Sprintf("%v", err))
+			return err
+		}
+	}
+
+	if err := tagAndPushRelease(repository, newRelease.Version, string(newRelease.Changelog), tokenFile, host, tagSign, pfmt); err != nil {
+		pfmt.Err(fmt.Sprintf("%v", err))
+		return err
+	}
+
+	err = publisher.Publish(newRelease, pfmt, tokenFile, assetPaths...)
 	if err != nil {
 		pfmt.Err(fmt.Sprintf("%v", err))
 		return err
 	}
 
+	if err := prependChangelog(changelogFile, string(newRelease.Changelog)); err != nil {
+		pfmt.Err(fmt.Sprintf("Could not update %q; %v", changelogFile, err))
+		return err
+	}
+	pfmt.Success(fmt.Sprintf("Updated %s", changelogFile))
+
 	pfmt.Success("Finished release!")
 	return nil
 }
@@ -316,37 +509,6 @@ func fileExists(filename string) bool {
 	return !info.IsDir() // Ensure it's not a directory, just a file.
 }
 
-func getOrgAndRepo(repo *git.Repository) (string, error) {
-	remoteConfig, err := repo.Remote("origin")
-	if err != nil {
-		return "", fmt.Errorf("could not get origin remote: %w", err)
-	}
-
-	// Extract the URL from the remote configuration
-	remoteURL := remoteConfig.Config().URLs[0]
-
-	// Handle both SSH and HTTPS URLs
-	if strings.HasPrefix(remoteURL, "git@") {
-		// SSH format: git@github.com:owner/repo.git
-		parts := strings.Split(strings.TrimSuffix(remoteURL, ".git"), ":")
-		if len(parts) != 2 {
-			return "", fmt.Errorf("unexpected SSH URL format")
-		}
-		return parts[1], nil
-	} else {
-		// HTTPS format: https://github.com/owner/repo.git
-		parsedURL, err := url.Parse(remoteURL)
-		if err != nil {
-			return "", fmt.Errorf("could not parse remote URL: %w", err)
-		}
-		parts := strings.Split(strings.TrimSuffix(parsedURL.Path, ".git"), "/")
-		if len(parts) < 2 {
-			return "", fmt.Errorf("unexpected HTTPS URL format")
-		}
-		return parts[len(parts)-2] + "/" + parts[len(parts)-1], nil
-	}
-}
-
 func humanizeVersion(version string) string {
 	semver, hash, err := strings.Cut(version, "_")
 	if !err {
@@ -359,8 +521,26 @@ func main() {
 	rootCmd.SetVersionTemplate(humanizeVersion(appVersion))
 	rootCmd.Flags().StringP("semver", "s", "", "The semver version string of the new release; If this is not included release will prompt for it.")
 	rootCmd.Flags().StringP("github_token_file", "g", "", "Github api key file (default is $HOME/.github_token)")
-	rootCmd.Flags().StringP("chatgpt_token_file", "c", "", "ChatGPT api key file (default is $HOME/.chatgpt_token)")
-	rootCmd.Flags().BoolP("chatgpt", "l", false, "Use ChatGPT to help you write changelogs")
+	rootCmd.Flags().String("gitlab_token_file", "", "Gitlab api key file (default is $HOME/.gitlab_token)")
+	rootCmd.Flags().String("host", "", "Git hosting provider to release to; accepted values are 'github', 'gitlab'; "+
+		"defaults to autodetecting from the origin remote's hostname")
+	rootCmd.Flags().String("api-url", "", "Base API URL to use for self-hosted instances, ex. a self-hosted GitLab's API root")
+	rootCmd.Flags().StringP("llm_token_file", "c", "", "LLM provider api key file (default is $HOME/.<provider>_token)")
+	rootCmd.Flags().BoolP("llm", "l", false, "Use an LLM to help you write changelogs")
+	rootCmd.Flags().String("llm-provider", string(openaiProvider), "LLM backend to use; accepted values are 'openai', 'anthropic', 'ollama', 'azure-openai'")
+	rootCmd.Flags().String("llm-model", "", "Model/deployment name to request from the LLM provider; defaults to a sensible model per provider")
+	rootCmd.Flags().String("llm-endpoint", "", "Base URL for the LLM provider; required for 'azure-openai', optional override for 'ollama'")
+	rootCmd.Flags().String("changelog-file", "CHANGELOG.md", "File to prepend the new release's changelog section to")
+	rootCmd.Flags().String("changelog-template", "", "Path to a Go text/template file to render the changelog with, instead of the built-in default")
+	rootCmd.Flags().Bool("dry-run", false, "Render the release plan (tag, title, body, resolved assets) without publishing anything to GitHub")
+	rootCmd.Flags().String("dry-run-output", "", "File to write the --dry-run release plan JSON to (default is stdout)")
+	rootCmd.Flags().Bool("sign", false, "Sign the release tag; defaults to on if user.signingkey or tag.gpgSign is set in git config")
+	rootCmd.Flags().Bool("no-sign", false, "Never sign the release tag, even if user.signingkey or tag.gpgSign is set in git config")
+	rootCmd.Flags().String("signing-key", "", "Key to sign the release tag with (a GPG key ID, or an SSH private key path with --signer ssh); defaults to git config's user.signingkey")
+	rootCmd.Flags().String("signer", "", "Tool to sign the release tag with; accepted values are 'gpg', 'ssh'; defaults to git config's gpg.format")
+	rootCmd.Flags().Bool("overwrite", false, "Delete and recreate the github release (and its tag) if one already exists for this version; github only")
+	rootCmd.Flags().String("from", "", "Revision to start the changelog/commit range at (exclusive); defaults to the latest semver tag reachable from --to")
+	rootCmd.Flags().String("to", "", "Revision to end the changelog/commit range at (inclusive); defaults to HEAD")
 	rootCmd.Flags().StringArrayP("asset", "a", []string{}, "Assets to upload; This is usually the binary of "+
 		"the software or anything else that needs to be attached to the release."+
 		" This flag also supports globbing; make sure to wrap the path in quotes to avoid shell auto-globbing.")
@@ -0,0 +1,578 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Masterminds/semver"
+	"github.com/clintjedwards/polyfmt/v2"
+	"github.com/clintjedwards/toolkit/workflow"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/google/go-github/github"
+	"github.com/spf13/cobra"
+)
+
+const (
+	releaseWorkflowStateFile     = ".release-workflow.json"
+	releaseWorkflowParamsFile    = ".release-workflow-params.json"
+	releaseWorkflowArtifactsFile = ".release-workflow-artifacts.json"
+)
+
+// releaseWorkflowParams is the set of inputs a `release run` invocation needs, persisted
+// alongside the workflow state so that `release resume`/`release status`, which are usually run
+// from a separate process invocation, don't need the caller to repeat them.
+type releaseWorkflowParams struct {
+	Semver            string   `json:"semver"`
+	Assets            []string `json:"assets"`
+	GithubTokenFile   string   `json:"github_token_file"`
+	ChangelogTemplate string   `json:"changelog_template"`
+	From              string   `json:"from"`
+	To                string   `json:"to"`
+}
+
+// releaseCommitArtifact is a JSON-serializable stand-in for commitInfo. *object.Commit doesn't
+// round-trip through JSON, and a resumed run may skip select-commits entirely (it already
+// succeeded), so render-changelog needs this on disk rather than passed through a Go closure.
+type releaseCommitArtifact struct {
+	Kind     string `json:"kind"`
+	Scope    string `json:"scope"`
+	Hash     string `json:"hash"`
+	Message  string `json:"message"`
+	Breaking bool   `json:"breaking"`
+}
+
+// releaseWorkflowArtifacts is what select-commits and render-changelog hand off to the steps
+// that depend on them. Steps read and write this file directly, rather than sharing state
+// through closures, so that a step skipped on resume doesn't leave later steps without input.
+type releaseWorkflowArtifacts struct {
+	Hostname         string                  `json:"hostname"`
+	OrgAndRepo       string                  `json:"org_and_repo"`
+	Version          string                  `json:"version"`
+	Date             string                  `json:"date"`
+	ParsedCommits    []releaseCommitArtifact `json:"parsed_commits"`
+	MalformedCommits []string                `json:"malformed_commits"`
+	Changelog        string                  `json:"changelog"`
+}
+
+func loadReleaseWorkflowParams(path string) (releaseWorkflowParams, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return releaseWorkflowParams{}, fmt.Errorf("could not read workflow params %q; has `release run` been started?: %w", path, err)
+	}
+
+	var params releaseWorkflowParams
+	if err := json.Unmarshal(contents, &params); err != nil {
+		return releaseWorkflowParams{}, fmt.Errorf("could not parse workflow params %q: %w", path, err)
+	}
+
+	return params, nil
+}
+
+func saveReleaseWorkflowParams(path string, params releaseWorkflowParams) error {
+	contents, err := json.MarshalIndent(params, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal workflow params: %w", err)
+	}
+
+	return os.WriteFile(path, contents, 0o644)
+}
+
+func loadReleaseWorkflowArtifacts(path string) (releaseWorkflowArtifacts, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return releaseWorkflowArtifacts{}, fmt.Errorf("could not read workflow artifacts %q: %w", path, err)
+	}
+
+	var artifacts releaseWorkflowArtifacts
+	if err := json.Unmarshal(contents, &artifacts); err != nil {
+		return releaseWorkflowArtifacts{}, fmt.Errorf("could not parse workflow artifacts %q: %w", path, err)
+	}
+
+	return artifacts, nil
+}
+
+func saveReleaseWorkflowArtifacts(path string, artifacts releaseWorkflowArtifacts) error {
+	contents, err := json.MarshalIndent(artifacts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal workflow artifacts: %w", err)
+	}
+
+	return os.WriteFile(path, contents, 0o644)
+}
+
+// buildReleaseWorkflow assembles the select-commits -> render-changelog -> tag-repo ->
+// create-github-release -> upload-asset[N] graph. Every step is safe to re-run: select-commits
+// and render-changelog simply recompute and overwrite their artifacts, while tag-repo,
+// create-github-release, and upload-asset each check whether their side effect already happened
+// before repeating it, so a `release resume` after a transient failure never re-tags or
+// re-publishes work that already landed.
+func buildReleaseWorkflow(repository *git.Repository, params releaseWorkflowParams, pfmt polyfmt.Formatter) *workflow.Workflow {
+	steps := []*workflow.Step{
+		{
+			Name: "select-commits",
+			Run:  func() error { return stepSelectCommits(repository, params, pfmt) },
+		},
+		{
+			Name:      "render-changelog",
+			DependsOn: []string{"select-commits"},
+			Run:       func() error { return stepRenderChangelog(params, pfmt) },
+		},
+		{
+			Name:      "tag-repo",
+			DependsOn: []string{"render-changelog"},
+			Run:       func() error { return stepTagRepo(repository, params, pfmt) },
+		},
+		{
+			Name:      "create-github-release",
+			DependsOn: []string{"tag-repo"},
+			Run:       func() error { return stepCreateGithubRelease(params, pfmt) },
+		},
+	}
+
+	for i, assetPath := range params.Assets {
+		assetPath := assetPath
+		steps = append(steps, &workflow.Step{
+			Name:      fmt.Sprintf("upload-asset[%d]", i),
+			DependsOn: []string{"create-github-release"},
+			Run:       func() error { return stepUploadAsset(params, pfmt, assetPath) },
+		})
+	}
+
+	return workflow.New(releaseWorkflowStateFile, steps...)
+}
+
+// stepSelectCommits resolves the repository's org/repo, the commits since the last release, and
+// the version to cut, then writes them to releaseWorkflowArtifactsFile for later steps.
+func stepSelectCommits(repository *git.Repository, params releaseWorkflowParams, pfmt polyfmt.Formatter) error {
+	pfmt.Print("Selecting commits since the last release")
+
+	hostname, orgAndRepo, err := getOrgAndRepo(repository)
+	if err != nil {
+		return fmt.Errorf("could not parse repository name: %w", err)
+	}
+
+	host, ok := detectGitHost(hostname)
+	if !ok || host != githubHost {
+		return fmt.Errorf("release run currently only supports github.com repositories; origin points at %q", hostname)
+	}
+
+	latestTag, commits, err := commitsInRange(repository, params.From, params.To)
+	if err != nil {
+		if params.From != "" || params.To != "" {
+			return fmt.Errorf("could not select commits for --from/--to range: %w", err)
+		}
+		latestTag, commits = nil, nil
+	}
+
+	parsedCommits, malformedCommits := parseConventionalCommits(commits)
+
+	version := params.Semver
+	switch {
+	case version != "":
+		if _, err := semver.NewVersion(version); err != nil {
+			return fmt.Errorf("could not parse semver %q: %w", version, err)
+		}
+	case latestTag == nil:
+		return fmt.Errorf("no previous release found; pass --semver to set the first version explicitly")
+	default:
+		cfg, err := loadReleaseConfig(releaseConfigFileName)
+		if err != nil {
+			return err
+		}
+
+		version, err = nextVersion(getSemverFromTag(latestTag), parsedCommits, cfg)
+		if err != nil {
+			return fmt.Errorf("could not compute next version: %w", err)
+		}
+	}
+
+	newRel, err := newRelease(version, orgAndRepo)
+	if err != nil {
+		return err
+	}
+
+	artifactCommits := make([]releaseCommitArtifact, 0, len(parsedCommits))
+	for _, c := range parsedCommits {
+		artifactCommits = append(artifactCommits, releaseCommitArtifact{
+			Kind:     string(c.kind),
+			Scope:    c.scope,
+			Hash:     c.commit.Hash.String(),
+			Message:  c.commit.Message,
+			Breaking: c.breaking,
+		})
+	}
+
+	artifacts := releaseWorkflowArtifacts{
+		Hostname:         hostname,
+		OrgAndRepo:       orgAndRepo,
+		Version:          newRel.Version,
+		Date:             newRel.Date,
+		ParsedCommits:    artifactCommits,
+		MalformedCommits: malformedCommits,
+	}
+
+	if err := saveReleaseWorkflowArtifacts(releaseWorkflowArtifactsFile, artifacts); err != nil {
+		return err
+	}
+
+	pfmt.Success(fmt.Sprintf("Selected %d commit(s) for v%s", len(commits), version))
+	return nil
+}
+
+// stepRenderChangelog renders the Markdown changelog section from the commits select-commits
+// recorded and writes it back into the same artifacts file.
+func stepRenderChangelog(params releaseWorkflowParams, pfmt polyfmt.Formatter) error {
+	pfmt.Print("Rendering changelog")
+
+	artifacts, err := loadReleaseWorkflowArtifacts(releaseWorkflowArtifactsFile)
+	if err != nil {
+		return err
+	}
+
+	parsedCommits := make([]commitInfo, 0, len(artifacts.ParsedCommits))
+	for _, c := range artifacts.ParsedCommits {
+		parsedCommits = append(parsedCommits, commitInfo{
+			kind:     commitType(c.Kind),
+			scope:    c.Scope,
+			breaking: c.Breaking,
+			commit:   &object.Commit{Hash: plumbing.NewHash(c.Hash), Message: c.Message},
+		})
+	}
+
+	changelog, err := renderChangelogSection(githubHost, artifacts.Hostname, artifacts.OrgAndRepo, artifacts.Version, artifacts.Date, parsedCommits, artifacts.MalformedCommits, params.ChangelogTemplate)
+	if err != nil {
+		return err
+	}
+	artifacts.Changelog = changelog
+
+	if err := saveReleaseWorkflowArtifacts(releaseWorkflowArtifactsFile, artifacts); err != nil {
+		return err
+	}
+
+	pfmt.Success("Rendered changelog")
+	return nil
+}
+
+// stepTagRepo creates and pushes the release tag, skipping creation if it's already there so a
+// resumed run doesn't try to recreate a tag that exists from a prior attempt.
+func stepTagRepo(repository *git.Repository, params releaseWorkflowParams, pfmt polyfmt.Formatter) error {
+	artifacts, err := loadReleaseWorkflowArtifacts(releaseWorkflowArtifactsFile)
+	if err != nil {
+		return err
+	}
+
+	tagName := "v" + artifacts.Version
+	_, err = repository.Tag(tagName)
+	if err == nil {
+		pfmt.Success(fmt.Sprintf("Tag %q already exists", tagName))
+		return nil
+	}
+	if err != git.ErrTagNotFound {
+		return fmt.Errorf("could not check for existing tag %q: %w", tagName, err)
+	}
+
+	return tagAndPushRelease(repository, artifacts.Version, artifacts.Changelog, params.GithubTokenFile, githubHost, nil, pfmt)
+}
+
+// stepCreateGithubRelease creates the GitHub release for the tagged version, skipping creation
+// if a release for that tag already exists.
+func stepCreateGithubRelease(params releaseWorkflowParams, pfmt polyfmt.Formatter) error {
+	artifacts, err := loadReleaseWorkflowArtifacts(releaseWorkflowArtifactsFile)
+	if err != nil {
+		return err
+	}
+
+	r, client, err := releaseAndClientFromArtifacts(artifacts, params)
+	if err != nil {
+		return err
+	}
+
+	existing, err := r.getExistingGithubRelease(client)
+	if err != nil {
+		return fmt.Errorf("could not check for existing release: %w", err)
+	}
+	if existing != nil {
+		pfmt.Success(fmt.Sprintf("Release %q already exists", existing.GetTagName()))
+		return nil
+	}
+
+	release := &github.RepositoryRelease{
+		TagName: github.String("v" + r.Version),
+		Name:    github.String("v" + r.Version),
+		Body:    github.String(artifacts.Changelog),
+	}
+
+	pfmt.Print("Creating release")
+	_, _, err = client.Repositories.CreateRelease(context.Background(), r.Organization, r.Repository, release)
+	if err != nil {
+		return fmt.Errorf("could not create release: %w", err)
+	}
+
+	pfmt.Success("Successfully created release!")
+	return nil
+}
+
+// stepUploadAsset uploads a single asset to the already-created release, skipping the upload if
+// an asset with that filename is already attached.
+func stepUploadAsset(params releaseWorkflowParams, pfmt polyfmt.Formatter, assetPath string) error {
+	artifacts, err := loadReleaseWorkflowArtifacts(releaseWorkflowArtifactsFile)
+	if err != nil {
+		return err
+	}
+
+	r, client, err := releaseAndClientFromArtifacts(artifacts, params)
+	if err != nil {
+		return err
+	}
+
+	release, err := r.getExistingGithubRelease(client)
+	if err != nil {
+		return fmt.Errorf("could not look up release: %w", err)
+	}
+	if release == nil {
+		return fmt.Errorf("release v%s does not exist yet; create-github-release must succeed first", r.Version)
+	}
+
+	pfmt.Print(fmt.Sprintf("Uploading asset: %q", assetPath))
+	if err := r.uploadAssetIfMissing(assetPath, release, client); err != nil {
+		return fmt.Errorf("could not upload asset %q: %w", assetPath, err)
+	}
+
+	pfmt.Success(fmt.Sprintf("Uploaded asset: %q", assetPath))
+	return nil
+}
+
+// releaseAndClientFromArtifacts rebuilds the minimal Release and authenticated github.Client a
+// release-publishing step needs, from the artifacts select-commits recorded.
+func releaseAndClientFromArtifacts(artifacts releaseWorkflowArtifacts, params releaseWorkflowParams) (*Release, *github.Client, error) {
+	org, repo, err := parseGithubURL(artifacts.OrgAndRepo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r := &Release{
+		Organization: org,
+		Repository:   repo,
+		OrgAndRepo:   artifacts.OrgAndRepo,
+		Version:      artifacts.Version,
+		Date:         artifacts.Date,
+	}
+
+	client, err := newGithubClient(params.GithubTokenFile, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return r, client, nil
+}
+
+var releaseRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Cut a release through a resumable, step-by-step workflow",
+	Long: `Runs the release as a graph of named steps (select-commits, render-changelog, tag-repo,
+create-github-release, upload-asset[N]), persisting each step's status to .release-workflow.json
+as it goes. Unlike the default interactive command, a failure partway through (a transient
+GitHub 5xx, a flaky asset upload) doesn't force starting over from scratch; run "release resume"
+to pick back up at the failed step. Currently only supports github.com repositories.`,
+	RunE: runReleaseRun,
+}
+
+var releaseResumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume a release workflow that previously failed or was interrupted",
+	RunE:  runReleaseResume,
+}
+
+var releaseStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Print the status of each step in the most recent release workflow run",
+	RunE:  runReleaseStatus,
+}
+
+func init() {
+	releaseRunCmd.Flags().StringP("semver", "s", "", "The semver version string of the new release; computed from conventional commits if omitted")
+	releaseRunCmd.Flags().StringArrayP("asset", "a", []string{}, "Assets to upload; this flag also supports globbing")
+	releaseRunCmd.Flags().StringP("github_token_file", "g", "", "Github api key file (default is $HOME/.github_token)")
+	releaseRunCmd.Flags().String("changelog-template", "", "Path to a Go text/template file to render the changelog with, instead of the built-in default")
+	releaseRunCmd.Flags().String("from", "", "Revision to start the changelog/commit range at (exclusive); defaults to the latest semver tag reachable from --to")
+	releaseRunCmd.Flags().String("to", "", "Revision to end the changelog/commit range at (inclusive); defaults to HEAD")
+
+	rootCmd.AddCommand(releaseRunCmd)
+	rootCmd.AddCommand(releaseResumeCmd)
+	rootCmd.AddCommand(releaseStatusCmd)
+}
+
+func runReleaseRun(cmd *cobra.Command, _ []string) error {
+	pfmt, err := newReleaseWorkflowFormatter(cmd)
+	if err != nil {
+		return err
+	}
+	defer pfmt.Finish()
+
+	if _, err := os.Stat(releaseWorkflowStateFile); err == nil {
+		err := fmt.Errorf("a release workflow is already in progress (%s exists); use `release resume` or `release status`, or remove it to start over", releaseWorkflowStateFile)
+		pfmt.Err(fmt.Sprintf("%v", err))
+		return err
+	}
+
+	repository, err := git.PlainOpen(".")
+	if err != nil {
+		pfmt.Err(fmt.Sprintf("Could not open local repository: %v", err))
+		return err
+	}
+
+	semverFlag, err := cmd.Flags().GetString("semver")
+	if err != nil {
+		panic(err)
+	}
+	assets, err := cmd.Flags().GetStringArray("asset")
+	if err != nil {
+		panic(err)
+	}
+	githubTokenFile, err := cmd.Flags().GetString("github_token_file")
+	if err != nil {
+		panic(err)
+	}
+	changelogTemplate, err := cmd.Flags().GetString("changelog-template")
+	if err != nil {
+		panic(err)
+	}
+	fromRev, err := cmd.Flags().GetString("from")
+	if err != nil {
+		panic(err)
+	}
+	toRev, err := cmd.Flags().GetString("to")
+	if err != nil {
+		panic(err)
+	}
+
+	assetPaths := []string{}
+	for assetPath, err := range resolveFilePaths(assets) {
+		if err != nil {
+			pfmt.Err(fmt.Sprintf("Could not find or resolve asset at path %q", assetPath))
+			continue
+		}
+		assetPaths = append(assetPaths, assetPath)
+	}
+
+	params := releaseWorkflowParams{
+		Semver:            semverFlag,
+		Assets:            assetPaths,
+		GithubTokenFile:   githubTokenFile,
+		ChangelogTemplate: changelogTemplate,
+		From:              fromRev,
+		To:                toRev,
+	}
+
+	if err := saveReleaseWorkflowParams(releaseWorkflowParamsFile, params); err != nil {
+		pfmt.Err(fmt.Sprintf("%v", err))
+		return err
+	}
+
+	return runReleaseWorkflow(buildReleaseWorkflow(repository, params, pfmt), pfmt)
+}
+
+func runReleaseResume(cmd *cobra.Command, _ []string) error {
+	pfmt, err := newReleaseWorkflowFormatter(cmd)
+	if err != nil {
+		return err
+	}
+	defer pfmt.Finish()
+
+	w, _, err := loadReleaseWorkflow(pfmt)
+	if err != nil {
+		return err
+	}
+
+	return runReleaseWorkflow(w, pfmt)
+}
+
+func runReleaseStatus(cmd *cobra.Command, _ []string) error {
+	pfmt, err := newReleaseWorkflowFormatter(cmd)
+	if err != nil {
+		return err
+	}
+	defer pfmt.Finish()
+
+	w, _, err := loadReleaseWorkflow(pfmt)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range w.Status() {
+		line := fmt.Sprintf("%-24s %s", s.Name, s.Status)
+		if s.Error != "" {
+			line += fmt.Sprintf(" (%s)", s.Error)
+		}
+		pfmt.Println(line)
+	}
+
+	return nil
+}
+
+// loadReleaseWorkflow rebuilds the release workflow from its persisted params and state, ready
+// for `release resume`/`release status` to act on.
+func loadReleaseWorkflow(pfmt polyfmt.Formatter) (*workflow.Workflow, releaseWorkflowParams, error) {
+	params, err := loadReleaseWorkflowParams(releaseWorkflowParamsFile)
+	if err != nil {
+		pfmt.Err(fmt.Sprintf("%v", err))
+		return nil, releaseWorkflowParams{}, err
+	}
+
+	repository, err := git.PlainOpen(".")
+	if err != nil {
+		pfmt.Err(fmt.Sprintf("Could not open local repository: %v", err))
+		return nil, releaseWorkflowParams{}, err
+	}
+
+	w := buildReleaseWorkflow(repository, params, pfmt)
+	if err := w.Load(); err != nil {
+		pfmt.Err(fmt.Sprintf("%v", err))
+		return nil, releaseWorkflowParams{}, err
+	}
+
+	return w, params, nil
+}
+
+// runReleaseWorkflow runs w to completion or its first failure, announcing each step as it
+// starts. Once every step has succeeded, the on-disk state, params, and artifacts are cleared so
+// the next `release run` in this checkout starts a fresh workflow instead of immediately hitting
+// the "a release workflow is already in progress" guard.
+func runReleaseWorkflow(w *workflow.Workflow, pfmt polyfmt.Formatter) error {
+	err := w.Run(func(name string) {
+		pfmt.Println(fmt.Sprintf("→ %s", name))
+	})
+	if err != nil {
+		pfmt.Err(fmt.Sprintf("%v", err))
+		return err
+	}
+
+	if err := w.Clear(); err != nil {
+		pfmt.Err(fmt.Sprintf("%v", err))
+		return err
+	}
+	for _, path := range []string{releaseWorkflowParamsFile, releaseWorkflowArtifactsFile} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			pfmt.Err(fmt.Sprintf("could not remove %q: %v", path, err))
+			return err
+		}
+	}
+
+	pfmt.Success("Finished release!")
+	return nil
+}
+
+// newReleaseWorkflowFormatter builds the polyfmt.Formatter for a workflow subcommand, honoring
+// the root command's persistent --format flag.
+func newReleaseWorkflowFormatter(cmd *cobra.Command) (polyfmt.Formatter, error) {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		panic(err)
+	}
+
+	return polyfmt.NewFormatter(polyfmt.Mode(format), polyfmt.DefaultOptions())
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/clintjedwards/polyfmt/v2"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"golang.org/x/crypto/openpgp"
+)
+
+// signerKind picks which tool a release tag's signature is produced with.
+type signerKind string
+
+const (
+	gpgSigner signerKind = "gpg"
+	sshSigner signerKind = "ssh"
+)
+
+// gitConfigSigningKey returns the configured `user.signingkey`, checking the repository's
+// local config first and falling back to the user's global git config.
+func gitConfigSigningKey(repo *git.Repository) (string, bool) {
+	if cfg, err := repo.Config(); err == nil {
+		if key := cfg.Raw.Section("user").Option("signingkey"); key != "" {
+			return key, true
+		}
+	}
+
+	if cfg, err := config.LoadConfig(config.GlobalScope); err == nil {
+		if key := cfg.Raw.Section("user").Option("signingkey"); key != "" {
+			return key, true
+		}
+	}
+
+	return "", false
+}
+
+// gitConfigTagGpgSign reports whether `tag.gpgSign` is set to true in the repository's local or
+// global git config, the same setting `git tag -a` honors to sign every annotated tag by default.
+func gitConfigTagGpgSign(repo *git.Repository) bool {
+	if cfg, err := repo.Config(); err == nil {
+		if value := cfg.Raw.Section("tag").Option("gpgSign"); value != "" {
+			return strings.EqualFold(value, "true")
+		}
+	}
+
+	if cfg, err := config.LoadConfig(config.GlobalScope); err == nil {
+		if value := cfg.Raw.Section("tag").Option("gpgSign"); value != "" {
+			return strings.EqualFold(value, "true")
+		}
+	}
+
+	return false
+}
+
+// gitConfigSignerKind returns the signerKind implied by `gpg.format`, checking the repository's
+// local config first and falling back to the user's global git config. Git defaults this to
+// "openpgp" when unset, so an empty or missing value means gpgSigner.
+func gitConfigSignerKind(repo *git.Repository) signerKind {
+	if cfg, err := repo.Config(); err == nil {
+		if format := cfg.Raw.Section("gpg").Option("format"); format == "ssh" {
+			return sshSigner
+		}
+	}
+
+	if cfg, err := config.LoadConfig(config.GlobalScope); err == nil {
+		if format := cfg.Raw.Section("gpg").Option("format"); format == "ssh" {
+			return sshSigner
+		}
+	}
+
+	return gpgSigner
+}
+
+// loadSigningKey exports the secret key identified by keyID from the user's local GPG keyring
+// (via the `gpg` binary) and parses it into an entity go-git can sign tags with. If the key is
+// passphrase-protected, the user is prompted for it.
+func loadSigningKey(keyID string, pfmt polyfmt.Formatter) (*openpgp.Entity, error) {
+	out, err := exec.Command("gpg", "--export-secret-keys", "--armor", keyID).Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not export secret key %q from gpg; is the key available and the agent unlocked?: %w", keyID, err)
+	}
+
+	entityList, err := openpgp.ReadArmoredKeyRing(strings.NewReader(string(out)))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse secret key %q: %w", keyID, err)
+	}
+
+	if len(entityList) == 0 {
+		return nil, fmt.Errorf("no secret key found for %q", keyID)
+	}
+
+	entity := entityList[0]
+
+	if entity.PrivateKey != nil && entity.PrivateKey.Encrypted {
+		passphrase := pfmt.Question(fmt.Sprintf("Passphrase for signing key %q: ", keyID))
+		if err := entity.PrivateKey.Decrypt([]byte(passphrase)); err != nil {
+			return nil, fmt.Errorf("could not decrypt signing key %q: %w", keyID, err)
+		}
+	}
+
+	return entity, nil
+}
+
+// signingKeyFingerprint returns the uppercased hex fingerprint of entity's primary key, the
+// same form `gpg --fingerprint` displays, so users can sanity check the identity before release.
+func signingKeyFingerprint(entity *openpgp.Entity) string {
+	return fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint)
+}
+
+// sshSignPayload signs payload with the private key at keyPath using `ssh-keygen -Y sign`, the
+// same mechanism `git` itself shells out to for SSH-format signatures. Namespace "git" matches
+// what `git` uses so the resulting signature verifies with `ssh-keygen -Y verify -n git`.
+func sshSignPayload(payload []byte, keyPath string) (string, error) {
+	tmp, err := os.CreateTemp("", "release-tag-*")
+	if err != nil {
+		return "", fmt.Errorf("could not create temp file to sign: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(payload); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("could not write payload to temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("could not close temp file: %w", err)
+	}
+
+	out, err := exec.Command("ssh-keygen", "-Y", "sign", "-n", "git", "-f", keyPath, tmp.Name()).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("could not sign with ssh key %q: %w (%s)", keyPath, err, strings.TrimSpace(string(out)))
+	}
+
+	sigPath := tmp.Name() + ".sig"
+	defer os.Remove(sigPath)
+
+	sig, err := os.ReadFile(sigPath)
+	if err != nil {
+		return "", fmt.Errorf("could not read ssh-keygen signature: %w", err)
+	}
+
+	return string(sig), nil
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/crypto/openpgp"
+)
+
+func TestSigningKeyFingerprint(t *testing.T) {
+	entity, err := openpgp.NewEntity("test", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := signingKeyFingerprint(entity)
+	want := fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint)
+
+	if got != want {
+		t.Errorf("wrong fingerprint; want %q; got %q", want, got)
+	}
+
+	if len(got) != 40 {
+		t.Errorf("expected a 40 character hex fingerprint; got %q (%d chars)", got, len(got))
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/clintjedwards/polyfmt/v2"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"golang.org/x/crypto/openpgp"
+)
+
+// tagSignConfig describes how tagAndPushRelease should sign the release tag. A nil value, or a
+// zero-value Signer, leaves the tag unsigned.
+type tagSignConfig struct {
+	Signer signerKind
+	// Entity is the loaded GPG key to sign with, used when Signer is gpgSigner.
+	Entity *openpgp.Entity
+	// SSHKeyPath is the private key file to sign with, used when Signer is sshSigner.
+	SSHKeyPath string
+}
+
+// tagAndPushRelease creates an annotated tag for the release at HEAD, with message as its body
+// and optionally signed per sign, and pushes it to origin so the release below is backed by a
+// real tag object instead of whatever tag the hosting provider's API happens to create.
+func tagAndPushRelease(repository *git.Repository, version, message, tokenFile string, host gitHost, sign *tagSignConfig, pfmt polyfmt.Formatter) error {
+	head, err := repository.Head()
+	if err != nil {
+		return fmt.Errorf("could not resolve HEAD: %w", err)
+	}
+
+	tagName := "v" + version
+	if message == "" {
+		message = tagName
+	}
+
+	pfmt.Print(fmt.Sprintf("Creating tag %q", tagName))
+	tagRef, err := createAnnotatedTag(repository, tagName, head.Hash(), message, sign)
+	if err != nil {
+		return fmt.Errorf("could not create tag %q: %w", tagName, err)
+	}
+
+	auth, err := pushAuth(repository, tokenFile, host)
+	if err != nil {
+		return fmt.Errorf("could not determine push credentials: %w", err)
+	}
+
+	pfmt.Print(fmt.Sprintf("Pushing tag %q to origin", tagName))
+	err = repository.Push(&git.PushOptions{
+		RemoteName: "origin",
+		RefSpecs:   []config.RefSpec{config.RefSpec(tagRef.Name() + ":" + tagRef.Name())},
+		Auth:       auth,
+	})
+	if err != nil {
+		return fmt.Errorf("could not push tag %q: %w", tagName, err)
+	}
+
+	pfmt.Success(fmt.Sprintf("Pushed tag %q", tagName))
+
+	return nil
+}
+
+// createAnnotatedTag creates an annotated tag named name at hash with message as its body. With
+// sign nil, or sign.Signer unset or gpgSigner, this is a thin wrapper around repository.CreateTag
+// (sign.Entity nil means unsigned). sign.Signer of sshSigner instead builds and stores the tag
+// object by hand, since go-git's CreateTagOptions only supports signing via an openpgp.Entity.
+func createAnnotatedTag(repository *git.Repository, name string, hash plumbing.Hash, message string, sign *tagSignConfig) (*plumbing.Reference, error) {
+	if sign == nil || sign.Signer != sshSigner {
+		var signKey *openpgp.Entity
+		if sign != nil {
+			signKey = sign.Entity
+		}
+
+		return repository.CreateTag(name, hash, &git.CreateTagOptions{
+			Message: message,
+			SignKey: signKey,
+		})
+	}
+
+	return createSSHSignedTag(repository, name, hash, message, sign.SSHKeyPath)
+}
+
+// createSSHSignedTag builds and stores an annotated tag object signed with the SSH key at
+// keyPath, mirroring what repository.CreateTag does internally for GPG signing but using
+// `ssh-keygen -Y sign` (via sshSignPayload) instead, since go-git has no native SSH signer.
+func createSSHSignedTag(repository *git.Repository, name string, hash plumbing.Hash, message, keyPath string) (*plumbing.Reference, error) {
+	rname := plumbing.ReferenceName(path.Join("refs", "tags", name))
+
+	if _, err := repository.Storer.Reference(rname); err == nil {
+		return nil, git.ErrTagExists
+	} else if err != plumbing.ErrReferenceNotFound {
+		return nil, err
+	}
+
+	tagger, err := tagTaggerFromConfig(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	rawobj, err := object.GetObject(repository.Storer, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := &object.Tag{
+		Name:       name,
+		Tagger:     *tagger,
+		Message:    strings.TrimSpace(message) + "\n",
+		TargetType: rawobj.Type(),
+		Target:     hash,
+	}
+
+	unsigned := &plumbing.MemoryObject{}
+	if err := tag.EncodeWithoutSignature(unsigned); err != nil {
+		return nil, err
+	}
+
+	rdr, err := unsigned.Reader()
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := io.ReadAll(rdr)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := sshSignPayload(payload, keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not sign tag %q: %w", name, err)
+	}
+	tag.PGPSignature = sig
+
+	obj := repository.Storer.NewEncodedObject()
+	if err := tag.Encode(obj); err != nil {
+		return nil, err
+	}
+
+	tagHash, err := repository.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := plumbing.NewHashReference(rname, tagHash)
+	if err := repository.Storer.SetReference(ref); err != nil {
+		return nil, err
+	}
+
+	return ref, nil
+}
+
+// tagTaggerFromConfig builds the tag.Tagger signature from the repository's configured author or
+// user identity, the same fallback order go-git's own CreateTagOptions.Validate uses.
+func tagTaggerFromConfig(repository *git.Repository) (*object.Signature, error) {
+	cfg, err := repository.Config()
+	if err != nil {
+		return nil, fmt.Errorf("could not read git config: %w", err)
+	}
+
+	if cfg.Author.Email != "" && cfg.Author.Name != "" {
+		return &object.Signature{Name: cfg.Author.Name, Email: cfg.Author.Email, When: time.Now()}, nil
+	}
+
+	if cfg.User.Email != "" && cfg.User.Name != "" {
+		return &object.Signature{Name: cfg.User.Name, Email: cfg.User.Email, When: time.Now()}, nil
+	}
+
+	return nil, fmt.Errorf("no tagger identity configured; set user.name and user.email in git config")
+}
+
+// pushAuth determines the credentials to push with based on origin's URL scheme; HTTPS remotes
+// reuse the same token release already uses to create the release (the username varies by host,
+// since that's what each provider expects for token-as-password auth), while SSH remotes fall
+// back to the local SSH agent.
+func pushAuth(repository *git.Repository, tokenFile string, host gitHost) (transport.AuthMethod, error) {
+	remoteConfig, err := repository.Remote("origin")
+	if err != nil {
+		return nil, fmt.Errorf("could not get origin remote: %w", err)
+	}
+
+	remoteURL := remoteConfig.Config().URLs[0]
+
+	if strings.HasPrefix(remoteURL, "git@") || strings.HasPrefix(remoteURL, "ssh://") {
+		auth, err := ssh.NewSSHAgentAuth(ssh.DefaultUsername)
+		if err != nil {
+			return nil, fmt.Errorf("could not set up SSH agent auth: %w", err)
+		}
+		return auth, nil
+	}
+
+	if host == gitlabHost {
+		token, err := getGitlabToken(tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not get gitlab token: %w", err)
+		}
+		return &githttp.BasicAuth{Username: "oauth2", Password: token}, nil
+	}
+
+	token, err := getGithubToken(tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not get github token: %w", err)
+	}
+
+	return &githttp.BasicAuth{Username: "x-access-token", Password: token}, nil
+}
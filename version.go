@@ -0,0 +1,275 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/Masterminds/semver"
+	"github.com/go-git/go-git/v5"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+const releaseConfigFileName = ".release.yaml"
+
+// bumpLevel describes how much a given commit kind should move the semver version.
+type bumpLevel string
+
+const (
+	bumpMajor bumpLevel = "major"
+	bumpMinor bumpLevel = "minor"
+	bumpPatch bumpLevel = "patch"
+	bumpNone  bumpLevel = "none"
+)
+
+var bumpLevelRank = map[bumpLevel]int{bumpNone: 0, bumpPatch: 1, bumpMinor: 2, bumpMajor: 3}
+
+// unknownKindPolicy controls what nextBumpLevel does when it encounters a commit kind that
+// isn't present in the configured bump rules.
+type unknownKindPolicy string
+
+const (
+	unknownKindIgnore unknownKindPolicy = "ignore"
+	unknownKindPatch  unknownKindPolicy = "patch"
+	unknownKindError  unknownKindPolicy = "error"
+)
+
+// releaseConfig is the user-tunable policy for how conventional commit kinds map to semver bumps.
+// It is loaded from .release.yaml in the repository root, falling back to defaultReleaseConfig
+// for anything not specified.
+type releaseConfig struct {
+	Bumps             map[commitType]bumpLevel `yaml:"bumps"`
+	UnknownKindPolicy unknownKindPolicy        `yaml:"unknown_kind_policy"`
+
+	// GithubBaseURL points the tool at a GitHub Enterprise Server instance instead of public
+	// github.com; overridden by the GITHUB_BASE_URL environment variable or --api-url.
+	GithubBaseURL string `yaml:"github_base_url"`
+
+	// InitialVersion is the version nextVersion seeds with when the repository has no prior
+	// releases to bump from, ex. "1.0.0" for projects that don't want to start at a 0.x version.
+	InitialVersion string `yaml:"initial_version"`
+}
+
+// defaultReleaseConfig mirrors the conventional-commit bump rules most projects expect:
+// feat bumps minor, fix/perf/refactor/revert bump patch, everything else bumps nothing.
+func defaultReleaseConfig() releaseConfig {
+	return releaseConfig{
+		Bumps: map[commitType]bumpLevel{
+			feat:     bumpMinor,
+			fix:      bumpPatch,
+			perf:     bumpPatch,
+			refactor: bumpPatch,
+			revert:   bumpPatch,
+			docs:     bumpNone,
+			ci:       bumpNone,
+			chore:    bumpNone,
+			other:    bumpNone,
+		},
+		UnknownKindPolicy: unknownKindIgnore,
+		InitialVersion:    "0.1.0",
+	}
+}
+
+// loadReleaseConfig reads bump rules from filename, merging them onto defaultReleaseConfig.
+// A missing file is not an error; it just means the defaults apply.
+func loadReleaseConfig(filename string) (releaseConfig, error) {
+	cfg := defaultReleaseConfig()
+
+	contents, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return releaseConfig{}, fmt.Errorf("could not read release config %q: %w", filename, err)
+	}
+
+	var userCfg releaseConfig
+	if err := yaml.Unmarshal(contents, &userCfg); err != nil {
+		return releaseConfig{}, fmt.Errorf("could not parse release config %q: %w", filename, err)
+	}
+
+	for kind, level := range userCfg.Bumps {
+		cfg.Bumps[kind] = level
+	}
+	if userCfg.UnknownKindPolicy != "" {
+		cfg.UnknownKindPolicy = userCfg.UnknownKindPolicy
+	}
+	if userCfg.GithubBaseURL != "" {
+		cfg.GithubBaseURL = userCfg.GithubBaseURL
+	}
+	if userCfg.InitialVersion != "" {
+		cfg.InitialVersion = userCfg.InitialVersion
+	}
+
+	return cfg, nil
+}
+
+// nextBumpLevel walks commits and returns the highest bump level they call for. A breaking
+// commit always forces a major bump, regardless of its kind.
+func nextBumpLevel(commits []commitInfo, cfg releaseConfig) (bumpLevel, error) {
+	highest := bumpNone
+
+	for _, c := range commits {
+		if c.breaking {
+			return bumpMajor, nil
+		}
+
+		level, found := cfg.Bumps[c.kind]
+		if !found {
+			switch cfg.UnknownKindPolicy {
+			case unknownKindError:
+				return bumpNone, fmt.Errorf("commit kind %q has no configured bump level", c.kind)
+			case unknownKindPatch:
+				level = bumpPatch
+			default:
+				level = bumpNone
+			}
+		}
+
+		if bumpLevelRank[level] > bumpLevelRank[highest] {
+			highest = level
+		}
+	}
+
+	return highest, nil
+}
+
+// nextVersion computes the semver that should follow currentVersion given the conventional
+// commits found since the last release. An empty currentVersion means the repository has no
+// prior release to bump from, so cfg.InitialVersion is returned as-is, regardless of what the
+// commits call for.
+func nextVersion(currentVersion string, commits []commitInfo, cfg releaseConfig) (string, error) {
+	if currentVersion == "" {
+		return cfg.InitialVersion, nil
+	}
+
+	current, err := semver.NewVersion(currentVersion)
+	if err != nil {
+		return "", fmt.Errorf("could not parse current version %q: %w", currentVersion, err)
+	}
+
+	level, err := nextBumpLevel(commits, cfg)
+	if err != nil {
+		return "", err
+	}
+
+	if level == bumpNone {
+		if next, ok := bumpRCPrerelease(current); ok {
+			return next, nil
+		}
+		return current.String(), nil
+	}
+
+	switch level {
+	case bumpMajor:
+		*current = current.IncMajor()
+	case bumpMinor:
+		*current = current.IncMinor()
+	case bumpPatch:
+		*current = current.IncPatch()
+	}
+
+	return current.String(), nil
+}
+
+// rcPrereleaseRe matches the "-rc.N" pre-release suffix bumpRCPrerelease knows how to
+// auto-increment.
+var rcPrereleaseRe = regexp.MustCompile(`^rc\.(\d+)$`)
+
+// bumpRCPrerelease increments a trailing "-rc.N" pre-release suffix on current, returning the
+// new version string and true if current had one; false (and an empty string) if current is a
+// plain release version, so the caller can fall back to leaving it unchanged.
+func bumpRCPrerelease(current *semver.Version) (string, bool) {
+	matches := rcPrereleaseRe.FindStringSubmatch(current.Prerelease())
+	if matches == nil {
+		return "", false
+	}
+
+	n, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return "", false
+	}
+
+	next, err := current.SetPrerelease(fmt.Sprintf("rc.%d", n+1))
+	if err != nil {
+		return "", false
+	}
+
+	return next.String(), true
+}
+
+var currentVersionCmd = &cobra.Command{
+	Use:     "current-version",
+	Aliases: []string{"cv"},
+	Short:   "Print the version of the latest tagged release",
+	RunE:    runCurrentVersion,
+}
+
+var nextVersionCmd = &cobra.Command{
+	Use:     "next-version",
+	Aliases: []string{"nv"},
+	Short:   "Print the next semver version based on conventional commits since the last release",
+	Long: `Walks commits since the latest tag and computes the next semantic version using the
+same conventional-commit bump rules the interactive release prompt defaults to, making it
+usable non-interactively from CI. Bump rules can be tuned with a .release.yaml file in the
+repository root.`,
+	RunE: runNextVersion,
+}
+
+func init() {
+	rootCmd.AddCommand(currentVersionCmd)
+	rootCmd.AddCommand(nextVersionCmd)
+}
+
+func runCurrentVersion(_ *cobra.Command, _ []string) error {
+	repository, err := git.PlainOpen(".")
+	if err != nil {
+		return fmt.Errorf("could not open local repository: %w", err)
+	}
+
+	latestTag, _, err := getCommitsAfterLatestTag(repository)
+	if err != nil {
+		return fmt.Errorf("could not find any previous releases: %w", err)
+	}
+
+	if latestTag == nil {
+		return fmt.Errorf("no previous releases found")
+	}
+
+	fmt.Println(getSemverFromTag(latestTag))
+	return nil
+}
+
+func runNextVersion(_ *cobra.Command, _ []string) error {
+	repository, err := git.PlainOpen(".")
+	if err != nil {
+		return fmt.Errorf("could not open local repository: %w", err)
+	}
+
+	latestTag, commits, err := getCommitsAfterLatestTag(repository)
+	if err != nil {
+		return fmt.Errorf("could not find any previous releases: %w", err)
+	}
+
+	currentVersion := ""
+	if latestTag != nil {
+		currentVersion = getSemverFromTag(latestTag)
+	}
+
+	cfg, err := loadReleaseConfig(releaseConfigFileName)
+	if err != nil {
+		return err
+	}
+
+	parsedCommits, _ := parseConventionalCommits(commits)
+
+	next, err := nextVersion(currentVersion, parsedCommits, cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(next)
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestNextBumpLevel(t *testing.T) {
+	tests := map[string]struct {
+		commits  []commitInfo
+		expected bumpLevel
+	}{
+		"feat bumps minor": {
+			commits:  []commitInfo{{kind: feat, commit: &object.Commit{}}},
+			expected: bumpMinor,
+		},
+		"fix bumps patch": {
+			commits:  []commitInfo{{kind: fix, commit: &object.Commit{}}},
+			expected: bumpPatch,
+		},
+		"docs bumps nothing": {
+			commits:  []commitInfo{{kind: docs, commit: &object.Commit{}}},
+			expected: bumpNone,
+		},
+		"breaking commit always bumps major": {
+			commits:  []commitInfo{{kind: docs, commit: &object.Commit{}, breaking: true}},
+			expected: bumpMajor,
+		},
+		"highest bump wins across commits": {
+			commits: []commitInfo{
+				{kind: fix, commit: &object.Commit{}},
+				{kind: feat, commit: &object.Commit{}},
+				{kind: docs, commit: &object.Commit{}},
+			},
+			expected: bumpMinor,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := nextBumpLevel(tc.commits, defaultReleaseConfig())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result != tc.expected {
+				t.Errorf("wrong bump level; want %q; got %q", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestNextBumpLevelUnknownKindPolicy(t *testing.T) {
+	commits := []commitInfo{{kind: other, commit: &object.Commit{}}}
+
+	cfg := defaultReleaseConfig()
+	delete(cfg.Bumps, other)
+
+	cfg.UnknownKindPolicy = unknownKindError
+	if _, err := nextBumpLevel(commits, cfg); err == nil {
+		t.Error("expected error for unknown kind with 'error' policy, got nil")
+	}
+
+	cfg.UnknownKindPolicy = unknownKindPatch
+	result, err := nextBumpLevel(commits, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != bumpPatch {
+		t.Errorf("wrong bump level for 'patch' unknown kind policy; want %q; got %q", bumpPatch, result)
+	}
+}
+
+func TestNextVersion(t *testing.T) {
+	tests := map[string]struct {
+		current  string
+		commits  []commitInfo
+		expected string
+	}{
+		"minor bump": {
+			current:  "1.2.3",
+			commits:  []commitInfo{{kind: feat, commit: &object.Commit{}}},
+			expected: "1.3.0",
+		},
+		"patch bump": {
+			current:  "1.2.3",
+			commits:  []commitInfo{{kind: fix, commit: &object.Commit{}}},
+			expected: "1.2.4",
+		},
+		"no bump leaves version unchanged": {
+			current:  "1.2.3",
+			commits:  []commitInfo{{kind: docs, commit: &object.Commit{}}},
+			expected: "1.2.3",
+		},
+		"breaking bumps major": {
+			current:  "1.2.3",
+			commits:  []commitInfo{{kind: feat, commit: &object.Commit{}, breaking: true}},
+			expected: "2.0.0",
+		},
+		"empty current seeds at the configured initial version": {
+			current:  "",
+			commits:  []commitInfo{{kind: feat, commit: &object.Commit{}}},
+			expected: "0.1.0",
+		},
+		"no bump increments an rc prerelease instead of leaving it unchanged": {
+			current:  "1.2.3-rc.1",
+			commits:  []commitInfo{{kind: docs, commit: &object.Commit{}}},
+			expected: "1.2.3-rc.2",
+		},
+		"a real bump finalizes the prerelease rather than incrementing it": {
+			current:  "1.2.3-rc.1",
+			commits:  []commitInfo{{kind: fix, commit: &object.Commit{}}},
+			expected: "1.2.3",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := nextVersion(tc.current, tc.commits, defaultReleaseConfig())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result != tc.expected {
+				t.Errorf("wrong next version; want %q; got %q", tc.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+// Package workflow implements a small, persisted task graph for multi-step operations (like
+// cutting a release) where any individual step can fail transiently and needs to be retried
+// without repeating the steps that already succeeded. It's deliberately minimal compared to
+// something like golang.org/x/build's tagx task runner: steps run in registration order, gated
+// on their declared dependencies having succeeded, with status written to a JSON state file
+// after every step.
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Status is the lifecycle state of a single Step within a Workflow run.
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// StepFunc does the actual work for a Step. It should be idempotent: Run can be called again
+// for the same Step after a previous attempt failed (or the process was killed mid-step), so
+// implementations should check whether their side effect already happened, ex. the tag or
+// release already exists, before repeating it.
+type StepFunc func() error
+
+// Step is a single named unit of work in a Workflow, gated on its DependsOn steps having
+// already succeeded.
+type Step struct {
+	Name      string
+	DependsOn []string
+	Run       StepFunc
+}
+
+// StepState is the persisted status of one Step, as written to the workflow's state file.
+type StepState struct {
+	Name      string    `json:"name"`
+	Status    Status    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// State is the full on-disk record of a workflow run, keyed by step name.
+type State struct {
+	Steps map[string]*StepState `json:"steps"`
+}
+
+// Workflow is a directed acyclic graph of Steps, executed in registration order with progress
+// persisted to statePath after every step so a later Load+Run can resume a run that failed or
+// was interrupted partway through.
+type Workflow struct {
+	statePath string
+	steps     []*Step
+	state     *State
+}
+
+// New builds a Workflow that persists its progress to statePath.
+func New(statePath string, steps ...*Step) *Workflow {
+	return &Workflow{
+		statePath: statePath,
+		steps:     steps,
+		state:     &State{Steps: map[string]*StepState{}},
+	}
+}
+
+// Load reads a previously persisted state file, if one exists, so a subsequent Run resumes
+// rather than starts fresh. A missing file is not an error.
+func (w *Workflow) Load() error {
+	contents, err := os.ReadFile(w.statePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not read workflow state %q: %w", w.statePath, err)
+	}
+
+	state := &State{}
+	if err := json.Unmarshal(contents, state); err != nil {
+		return fmt.Errorf("could not parse workflow state %q: %w", w.statePath, err)
+	}
+
+	w.state = state
+	return nil
+}
+
+// Save writes the current state to statePath.
+func (w *Workflow) Save() error {
+	contents, err := json.MarshalIndent(w.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal workflow state: %w", err)
+	}
+
+	if err := os.WriteFile(w.statePath, contents, 0o644); err != nil {
+		return fmt.Errorf("could not write workflow state %q: %w", w.statePath, err)
+	}
+
+	return nil
+}
+
+// Clear removes the persisted state file, ex. once a run has fully succeeded.
+func (w *Workflow) Clear() error {
+	err := os.Remove(w.statePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not remove workflow state %q: %w", w.statePath, err)
+	}
+	return nil
+}
+
+// Status returns the current status of every step, in registration order.
+func (w *Workflow) Status() []*StepState {
+	statuses := make([]*StepState, 0, len(w.steps))
+	for _, step := range w.steps {
+		if s, ok := w.state.Steps[step.Name]; ok {
+			statuses = append(statuses, s)
+			continue
+		}
+		statuses = append(statuses, &StepState{Name: step.Name, Status: StatusPending})
+	}
+	return statuses
+}
+
+// Run executes steps in registration order, skipping any already StatusSucceeded so a resumed
+// run doesn't repeat completed work, and stopping at the first step that fails. onStep, if
+// non-nil, is called right before each step runs so callers can surface progress through their
+// own formatter.
+func (w *Workflow) Run(onStep func(name string)) error {
+	for _, step := range w.steps {
+		if existing, ok := w.state.Steps[step.Name]; ok && existing.Status == StatusSucceeded {
+			continue
+		}
+
+		for _, dep := range step.DependsOn {
+			depState, ok := w.state.Steps[dep]
+			if !ok || depState.Status != StatusSucceeded {
+				return fmt.Errorf("cannot run step %q: dependency %q has not succeeded", step.Name, dep)
+			}
+		}
+
+		if onStep != nil {
+			onStep(step.Name)
+		}
+
+		w.setStatus(step.Name, StatusRunning, "")
+		if err := w.Save(); err != nil {
+			return err
+		}
+
+		if err := step.Run(); err != nil {
+			w.setStatus(step.Name, StatusFailed, err.Error())
+			_ = w.Save()
+			return fmt.Errorf("step %q failed: %w", step.Name, err)
+		}
+
+		w.setStatus(step.Name, StatusSucceeded, "")
+		if err := w.Save(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *Workflow) setStatus(name string, status Status, errMsg string) {
+	w.state.Steps[name] = &StepState{
+		Name:      name,
+		Status:    status,
+		Error:     errMsg,
+		UpdatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,95 @@
+package workflow
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunSkipsSucceededStepsOnResume(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	runs := 0
+	failOnce := true
+
+	steps := func() []*Step {
+		return []*Step{
+			{Name: "a", Run: func() error {
+				runs++
+				return nil
+			}},
+			{Name: "b", DependsOn: []string{"a"}, Run: func() error {
+				if failOnce {
+					failOnce = false
+					return errors.New("transient failure")
+				}
+				runs++
+				return nil
+			}},
+		}
+	}
+
+	w := New(statePath, steps()...)
+	if err := w.Run(nil); err == nil {
+		t.Fatal("expected first run to fail on step b")
+	}
+
+	if err := w.Save(); err != nil {
+		t.Fatalf("unexpected error saving state: %v", err)
+	}
+
+	// Simulate a fresh process resuming: a new Workflow loading the persisted state.
+	resumed := New(statePath, steps()...)
+	if err := resumed.Load(); err != nil {
+		t.Fatalf("unexpected error loading state: %v", err)
+	}
+
+	if err := resumed.Run(nil); err != nil {
+		t.Fatalf("unexpected error on resumed run: %v", err)
+	}
+
+	if runs != 2 {
+		t.Errorf("expected step a to run once and step b to run once across both attempts; got %d total runs", runs)
+	}
+
+	for _, s := range resumed.Status() {
+		if s.Status != StatusSucceeded {
+			t.Errorf("step %q: want status %q; got %q", s.Name, StatusSucceeded, s.Status)
+		}
+	}
+}
+
+func TestRunFailsWhenDependencyHasNotSucceeded(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	called := false
+	w := New(statePath,
+		&Step{Name: "a", Run: func() error { return errors.New("boom") }},
+		&Step{Name: "b", DependsOn: []string{"a"}, Run: func() error {
+			called = true
+			return nil
+		}},
+	)
+
+	if err := w.Run(nil); err == nil {
+		t.Fatal("expected run to fail")
+	}
+
+	if called {
+		t.Error("step b should not have run since its dependency a failed")
+	}
+}
+
+func TestStatusReportsPendingForUnseenSteps(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	w := New(statePath, &Step{Name: "a", Run: func() error { return nil }})
+
+	statuses := w.Status()
+	if len(statuses) != 1 {
+		t.Fatalf("expected 1 status; got %d", len(statuses))
+	}
+	if statuses[0].Status != StatusPending {
+		t.Errorf("want status %q; got %q", StatusPending, statuses[0].Status)
+	}
+}